@@ -6,8 +6,14 @@ import "reflect"
 type Jamf struct {
 	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
+	JamfClientId string `mapstructure:"jamf-client-id"`
+	JamfClientSecret string `mapstructure:"jamf-client-secret"`
 	InstanceUrl string `mapstructure:"instance-url"`
 	CreateAccountResourceType string `mapstructure:"create-account-resource-type"`
+	DisableUserAccountsOnDelete bool `mapstructure:"disable-user-accounts-on-delete"`
+	Concurrency int `mapstructure:"concurrency"`
+	RetryMaxAttempts int `mapstructure:"retry-max-attempts"`
+	RetryBudgetSeconds int `mapstructure:"retry-budget-seconds"`
 }
 
 func (c *Jamf) findFieldByTag(tagValue string) (any, bool) {
@@ -9,14 +9,28 @@ var (
 		"username",
 		field.WithDisplayName("Username"),
 		field.WithDescription("Username for your Jamf Pro instance"),
-		field.WithRequired(true),
 	)
 	PasswordField = field.StringField(
 		"password",
 		field.WithDisplayName("Password"),
 		field.WithDescription("Password for your Jamf Pro instance"),
 		field.WithIsSecret(true),
-		field.WithRequired(true),
+	)
+
+	// ClientIdField and ClientSecretField authenticate as a Jamf Pro API
+	// client (API Roles and Clients) instead of a user account. They're
+	// prefixed with "jamf-" because baton-sdk already reserves client-id and
+	// client-secret for the ConductorOne credentials.
+	ClientIdField = field.StringField(
+		"jamf-client-id",
+		field.WithDisplayName("API Client ID"),
+		field.WithDescription("Client ID of a Jamf Pro API client. Use instead of username and password"),
+	)
+	ClientSecretField = field.StringField(
+		"jamf-client-secret",
+		field.WithDisplayName("API Client Secret"),
+		field.WithDescription("Client secret of the Jamf Pro API client"),
+		field.WithIsSecret(true),
 	)
 	InstanceUrlField = field.StringField(
 		"instance-url",
@@ -44,18 +58,74 @@ var (
 		field.WithDefaultValue("user"),
 	).ExportAs(field.ExportTargetGUI)
 
+	// DisableUserAccountsOnDeleteField turns deleting a userAccount into
+	// disabling it, so the account's audit history stays in Jamf.
+	DisableUserAccountsOnDeleteField = field.BoolField(
+		"disable-user-accounts-on-delete",
+		field.WithDisplayName("Disable User Accounts Instead of Deleting"),
+		field.WithDescription("Disable Jamf Pro user accounts when they're deleted, rather than deleting them, so their audit history is kept"),
+		field.WithDefaultValue(false),
+	)
+
+	// ConcurrencyField bounds the per-object detail requests made while
+	// listing users, user groups and accounts — the Classic API has no bulk
+	// detail endpoint, so large tenants need these in parallel.
+	ConcurrencyField = field.IntField(
+		"concurrency",
+		field.WithDisplayName("Request Concurrency"),
+		field.WithDescription("How many Jamf detail requests to run at once while syncing users, user groups and accounts"),
+		field.WithDefaultValue(5),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gte(1).Lte(50)
+		}),
+	)
+
+	// RetryMaxAttemptsField and RetryBudgetField bound how rate-limited
+	// (429) and transiently failing (502/503/504) Jamf requests are retried.
+	// Jamf Cloud sheds load this way, so a large sync that gives up on the
+	// first one fails halfway through.
+	RetryMaxAttemptsField = field.IntField(
+		"retry-max-attempts",
+		field.WithDisplayName("Retry Max Attempts"),
+		field.WithDescription("How many times to send a Jamf request that is rate limited or fails transiently, counting the first attempt. 1 disables retries"),
+		field.WithDefaultValue(5),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gte(1).Lte(20)
+		}),
+	)
+	RetryBudgetField = field.IntField(
+		"retry-budget-seconds",
+		field.WithDisplayName("Retry Budget (seconds)"),
+		field.WithDescription("How long after its first attempt a Jamf request may still be retried"),
+		field.WithDefaultValue(120),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gte(0).Lte(3600)
+		}),
+	)
+
 	// ConfigurationFields defines the external configuration required for the
 	// connector to run.
 	ConfigurationFields = []field.SchemaField{
 		UsernameField,
 		PasswordField,
+		ClientIdField,
+		ClientSecretField,
 		InstanceUrlField,
 		CreateAccountResourceTypeField,
+		DisableUserAccountsOnDeleteField,
+		ConcurrencyField,
+		RetryMaxAttemptsField,
+		RetryBudgetField,
 	}
 
 	// FieldRelationships defines relationships between the fields listed in
 	// ConfigurationFields that can be automatically validated.
-	FieldRelationships = []field.SchemaFieldRelationship{}
+	FieldRelationships = []field.SchemaFieldRelationship{
+		field.FieldsRequiredTogether(UsernameField, PasswordField),
+		field.FieldsRequiredTogether(ClientIdField, ClientSecretField),
+		field.FieldsMutuallyExclusive(UsernameField, ClientIdField),
+		field.FieldsAtLeastOneUsed(UsernameField, ClientIdField),
+	}
 )
 
 //go:generate go run ./gen
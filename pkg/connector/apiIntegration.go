@@ -0,0 +1,91 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type apiIntegrationResourceType struct {
+	resourceType *v2.ResourceType
+	client       *jamf.Client
+}
+
+func (o *apiIntegrationResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Jamf Pro API integration. Integrations
+// are API clients — machine identities that act with the privileges of their
+// API roles — so they're marked as non-human app registrations.
+func apiIntegrationResource(integration *jamf.APIIntegration, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"api_integration_id":            integration.ID,
+		"api_integration_name":          integration.DisplayName,
+		"client_id":                     integration.ClientID,
+		"app_type":                      integration.AppType,
+		"access_token_lifetime_seconds": integration.AccessTokenLifetimeSeconds,
+		"authorization_scopes":          strings.Join(integration.AuthorizationScopes, ", "),
+	}
+
+	var appTraitOptions []rs.AppTraitOption
+	resourceStatus := v2.Status_RESOURCE_STATUS_ENABLED
+	if !integration.Enabled {
+		appTraitOptions = append(appTraitOptions, rs.WithAppFlags(v2.AppTrait_APP_FLAG_INACTIVE))
+		resourceStatus = v2.Status_RESOURCE_STATUS_DISABLED
+	}
+
+	ret, err := rs.NewAppResource(
+		integration.DisplayName,
+		resourceTypeAPIIntegration,
+		integration.ID,
+		appTraitOptions,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithResourceProfile(profile),
+		rs.WithResourceStatus(resourceStatus, ""),
+		rs.WithNHIType(v2.NonHumanIdentityTrait_NHI_TYPE_APP_REGISTRATION, "Jamf Pro API client"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *apiIntegrationResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	integrations, err := o.client.GetAPIIntegrations(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list API integrations: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for i := range integrations {
+		ir, err := apiIntegrationResource(&integrations[i], parentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, ir)
+	}
+
+	return rv, nil, nil
+}
+
+func (o *apiIntegrationResourceType) Entitlements(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	return nil, nil, nil
+}
+
+func (o *apiIntegrationResourceType) Grants(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	return nil, nil, nil
+}
+
+func apiIntegrationBuilder(client *jamf.Client) *apiIntegrationResourceType {
+	return &apiIntegrationResourceType{
+		resourceType: resourceTypeAPIIntegration,
+		client:       client,
+	}
+}
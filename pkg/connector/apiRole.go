@@ -0,0 +1,113 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type apiRoleResourceType struct {
+	resourceType *v2.ResourceType
+	client       *jamf.Client
+}
+
+func (o *apiRoleResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Jamf Pro API role.
+func apiRoleResource(role *jamf.APIRole, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"api_role_id":   role.ID,
+		"api_role_name": role.DisplayName,
+		"privileges":    strings.Join(role.Privileges, ", "),
+	}
+
+	ret, err := rs.NewRoleResource(
+		role.DisplayName,
+		resourceTypeAPIRole,
+		role.ID,
+		nil,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithResourceProfile(profile),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *apiRoleResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	roles, err := o.client.GetAPIRoles(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list API roles: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for i := range roles {
+		rr, err := apiRoleResource(&roles[i], parentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, rr)
+	}
+
+	return rv, nil, nil
+}
+
+func (o *apiRoleResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeAPIIntegration),
+		ent.WithDescription(fmt.Sprintf("API integrations authorized with the %s API role in Jamf", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s API Role %s", resource.DisplayName, memberEntitlement)),
+	}
+
+	en := ent.NewAssignmentEntitlement(resource, memberEntitlement, assignmentOptions...)
+	rv = append(rv, en)
+
+	return rv, nil, nil
+}
+
+// Grants links the role to every API integration whose authorization scopes
+// name it. Jamf records the scope by the role's display name, not its ID.
+func (o *apiRoleResourceType) Grants(ctx context.Context, resource *v2.Resource, attrs rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	integrations, err := o.client.GetAPIIntegrations(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list API integrations: %w", err)
+	}
+
+	var rv []*v2.Grant
+	for i := range integrations {
+		integration := &integrations[i]
+		if !slices.Contains(integration.AuthorizationScopes, resource.DisplayName) {
+			continue
+		}
+
+		ir, err := apiIntegrationResource(integration, resource.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, grant.NewGrant(resource, memberEntitlement, ir.Id))
+	}
+
+	return rv, nil, nil
+}
+
+func apiRoleBuilder(client *jamf.Client) *apiRoleResourceType {
+	return &apiRoleResourceType{
+		resourceType: resourceTypeAPIRole,
+		client:       client,
+	}
+}
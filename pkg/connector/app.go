@@ -0,0 +1,317 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const (
+	// Mac and mobile device apps are numbered separately in Jamf, so an app
+	// resource's ID is its platform and its Jamf ID. The platform is also the
+	// listing phase tracked in the pagination bag.
+	appPlatformMac    = "mac"
+	appPlatformMobile = "mobile"
+)
+
+type appResourceType struct {
+	resourceType *v2.ResourceType
+	client       *jamf.Client
+}
+
+func (o *appResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+func appObjectID(platform string, id int) string {
+	return fmt.Sprintf("%s:%d", platform, id)
+}
+
+// parseAppObjectID splits an app ID built by appObjectID back into its
+// platform and the Jamf ID of the app.
+func parseAppObjectID(objectID string) (string, int, error) {
+	platform, id, ok := strings.Cut(objectID, ":")
+	if !ok || (platform != appPlatformMac && platform != appPlatformMobile) {
+		return "", 0, fmt.Errorf("jamf-connector: invalid app id %q", objectID)
+	}
+	appId, err := strconv.Atoi(id)
+	if err != nil {
+		return "", 0, fmt.Errorf("jamf-connector: invalid app id %q: %w", objectID, err)
+	}
+	return platform, appId, nil
+}
+
+// Create a new connector resource for a Jamf Mac App Store app.
+func macApplicationResource(app *jamf.MacApplication, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	general := app.General
+	profile := map[string]interface{}{
+		"app_id":                general.ID,
+		"app_name":              general.Name,
+		"platform":              appPlatformMac,
+		"free":                  general.IsFree,
+		"device_based_licenses": app.VPP.AssignVPPDeviceBasedLicenses,
+	}
+	return appResource(general.Name, appPlatformMac, general.ID, profile, general.Version, general.BundleID,
+		general.DeploymentType, general.URL, general.Category, general.Site, parentResourceID)
+}
+
+// Create a new connector resource for a Jamf mobile device app.
+func mobileDeviceApplicationResource(app *jamf.MobileDeviceApplication, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	general := app.General
+	name := general.DisplayName
+	if name == "" {
+		name = general.Name
+	}
+	profile := map[string]interface{}{
+		"app_id":                general.ID,
+		"app_name":              name,
+		"platform":              appPlatformMobile,
+		"free":                  general.Free,
+		"device_based_licenses": app.VPP.AssignVPPDeviceBasedLicenses,
+	}
+	return appResource(name, appPlatformMobile, general.ID, profile, general.Version, general.BundleID,
+		general.DeploymentType, general.ITunesStoreURL, general.Category, general.Site, parentResourceID)
+}
+
+// appResource completes the profile of either kind of app and builds its
+// resource.
+func appResource(
+	name string,
+	platform string,
+	id int,
+	profile map[string]interface{},
+	version, bundleID, deploymentType, storeURL string,
+	category, site jamf.BaseType,
+	parentResourceID *v2.ResourceId,
+) (*v2.Resource, error) {
+	for key, value := range map[string]string{
+		"version":         version,
+		"bundle_id":       bundleID,
+		"deployment_type": deploymentType,
+		"category":        category.Name,
+	} {
+		if value != "" {
+			profile[key] = value
+		}
+	}
+	if site.Name != "" {
+		profile["site_id"] = site.ID
+		profile["site_name"] = site.Name
+	}
+
+	var appTraitOptions []rs.AppTraitOption
+	if adamID := jamf.AppStoreID(storeURL); adamID != 0 {
+		profile["app_store_id"] = adamID
+		appTraitOptions = append(appTraitOptions, rs.WithAppHelpURL(storeURL))
+	}
+
+	ret, err := rs.NewAppResource(
+		name,
+		resourceTypeApp,
+		appObjectID(platform, id),
+		appTraitOptions,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithResourceProfile(profile),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// List walks the Mac apps, then the mobile device apps.
+func (o *appResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	bag, offset, pageSize, err := parseOffsetPageToken(attrs.PageToken, appPlatformMac)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		rv       []*v2.Resource
+		total    int
+		platform = bag.Current().ResourceTypeID
+	)
+	switch platform {
+	case appPlatformMac:
+		var apps []*jamf.MacApplication
+		apps, total, err = o.client.GetMacApplicationsPage(ctx, offset, pageSize)
+		if err != nil {
+			return nil, nil, fmt.Errorf("jamf-connector: failed to list Mac apps: %w", err)
+		}
+		for _, app := range apps {
+			ar, err := macApplicationResource(app, parentId)
+			if err != nil {
+				return nil, nil, err
+			}
+			rv = append(rv, ar)
+		}
+
+	case appPlatformMobile:
+		var apps []*jamf.MobileDeviceApplication
+		apps, total, err = o.client.GetMobileDeviceApplicationsPage(ctx, offset, pageSize)
+		if err != nil {
+			return nil, nil, fmt.Errorf("jamf-connector: failed to list mobile device apps: %w", err)
+		}
+		for _, app := range apps {
+			ar, err := mobileDeviceApplicationResource(app, parentId)
+			if err != nil {
+				return nil, nil, err
+			}
+			rv = append(rv, ar)
+		}
+
+	default:
+		return nil, nil, fmt.Errorf("jamf-connector: unknown app platform %q", platform)
+	}
+
+	if platform == appPlatformMac && offset+pageSize >= total {
+		// Mac apps exhausted; advance to the mobile device apps.
+		bag.Pop()
+		bag.Push(pagination.PageState{ResourceTypeID: appPlatformMobile})
+		nextToken, err := bag.Marshal()
+		if err != nil {
+			return nil, nil, err
+		}
+		return rv, &rs.SyncOpResults{NextPageToken: nextToken}, nil
+	}
+
+	nextToken, err := nextOffsetPageToken(bag, offset+pageSize, total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rv, &rs.SyncOpResults{NextPageToken: nextToken}, nil
+}
+
+func (o *appResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(
+			resourceTypeUser, resourceTypeUserGroup,
+			resourceTypeManagedDevice, resourceTypeComputerGroup, resourceTypeMobileDeviceGroup,
+			resourceTypeBuilding, resourceTypeDepartment,
+		),
+		ent.WithDescription(fmt.Sprintf("Assigned the %s app in Jamf", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s App %s", resource.DisplayName, assignedEntitlement)),
+	}
+
+	en := ent.NewAssignmentEntitlement(resource, assignedEntitlement, assignmentOptions...)
+	rv = append(rv, en)
+
+	return rv, nil, nil
+}
+
+// Grants emits who and what the app is assigned to: the devices, device
+// groups, buildings and departments in its scope (see scopeGrants), the
+// users and user groups in its scope (see userScopeGrants), and the users
+// and user groups of every VPP assignment that licenses it.
+func (o *appResourceType) Grants(ctx context.Context, resource *v2.Resource, attrs rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	platform, appId, err := parseAppObjectID(resource.Id.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		devices                     *deviceScope
+		allUsers                    bool
+		userTargets, userExclusions *jamf.UserScopeTargets
+		adamID                      int
+		vppApps                     func(assignment *jamf.VPPAssignment) []jamf.VPPApp
+	)
+	switch platform {
+	case appPlatformMac:
+		app, err := o.client.GetMacApplicationDetails(ctx, appId)
+		if err != nil {
+			return nil, nil, err
+		}
+		computers := app.Scope.Computers()
+		devices = computerScope(&computers)
+		allUsers, userTargets, userExclusions = app.Scope.AllJSSUsers, &app.Scope.UserScopeTargets, &app.Scope.Exclusions.UserScopeTargets
+		adamID = jamf.AppStoreID(app.General.URL)
+		vppApps = func(assignment *jamf.VPPAssignment) []jamf.VPPApp { return assignment.MacApps }
+	default:
+		app, err := o.client.GetMobileDeviceApplicationDetails(ctx, appId)
+		if err != nil {
+			return nil, nil, err
+		}
+		mobileDevices := app.Scope.MobileDevices()
+		devices = mobileDeviceScope(&mobileDevices)
+		allUsers, userTargets, userExclusions = app.Scope.AllJSSUsers, &app.Scope.UserScopeTargets, &app.Scope.Exclusions.UserScopeTargets
+		adamID = jamf.AppStoreID(app.General.ITunesStoreURL)
+		vppApps = func(assignment *jamf.VPPAssignment) []jamf.VPPApp { return assignment.IOSApps }
+	}
+
+	rv, err := scopeGrants(ctx, o.client, resource, assignedEntitlement, devices)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	users, err := o.client.GetUsers(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list users: %w", err)
+	}
+	userGroups, err := o.client.GetUserGroups(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list user groups: %w", err)
+	}
+
+	userGrants, err := userScopeGrants(resource, assignedEntitlement, allUsers, userTargets, userExclusions, users, userGroups)
+	if err != nil {
+		return nil, nil, err
+	}
+	rv = append(rv, userGrants...)
+
+	if adamID != 0 {
+		assignments, err := o.client.GetVPPAssignments(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("jamf-connector: failed to list VPP assignments: %w", err)
+		}
+		for _, assignment := range assignments {
+			if !slices.ContainsFunc(vppApps(assignment), func(app jamf.VPPApp) bool { return app.AdamID == adamID }) {
+				continue
+			}
+			scope := &assignment.Scope
+			assignmentGrants, err := userScopeGrants(resource, assignedEntitlement, scope.AllJSSUsers, &scope.UserScopeTargets, &scope.Exclusions, users, userGroups)
+			if err != nil {
+				return nil, nil, err
+			}
+			rv = append(rv, assignmentGrants...)
+		}
+	}
+
+	return uniqueGrants(rv), nil, nil
+}
+
+// uniqueGrants drops all but the first grant to each principal, for a user
+// both in an app's scope and licensed it through a VPP assignment.
+func uniqueGrants(grants []*v2.Grant) []*v2.Grant {
+	seen := make(map[string]bool, len(grants))
+	return slices.DeleteFunc(grants, func(g *v2.Grant) bool {
+		principal := g.GetPrincipal().GetId()
+		key := principal.GetResourceType() + ":" + principal.GetResource()
+		if seen[key] {
+			return true
+		}
+		seen[key] = true
+		return false
+	})
+}
+
+func appBuilder(client *jamf.Client) *appResourceType {
+	return &appResourceType{
+		resourceType: resourceTypeApp,
+		client:       client,
+	}
+}
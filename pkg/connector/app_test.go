@@ -0,0 +1,71 @@
+package connector
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+)
+
+func TestUserScopeGrants(t *testing.T) {
+	app, err := macApplicationResource(&jamf.MacApplication{General: jamf.MacApplicationGeneral{BaseType: jamf.BaseType{ID: 901, Name: "Slack"}}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	users := []*jamf.User{
+		{BaseType: jamf.BaseType{ID: 1, Name: "john.appleseed"}},
+		{BaseType: jamf.BaseType{ID: 2, Name: "jane.doe"}},
+		{BaseType: jamf.BaseType{ID: 3, Name: "carol.smith"}},
+	}
+	userGroups := []*jamf.UserGroup{
+		{BaseType: jamf.BaseType{ID: 301}, Users: []jamf.User{{BaseType: jamf.BaseType{ID: 2}}}},
+	}
+	named := func(names ...string) []jamf.BaseType {
+		var rv []jamf.BaseType
+		for _, name := range names {
+			rv = append(rv, jamf.BaseType{Name: name})
+		}
+		return rv
+	}
+
+	tests := []struct {
+		name       string
+		all        bool
+		targets    jamf.UserScopeTargets
+		exclusions jamf.UserScopeTargets
+		want       []string
+	}{
+		{
+			name:    "named users and groups",
+			targets: jamf.UserScopeTargets{JSSUsers: named("carol.smith", "deleted.user"), JSSUserGroups: refs(302)},
+			want:    []string{"user:3", "userGroup:302"},
+		},
+		{
+			name:       "all users minus excluded group members",
+			all:        true,
+			exclusions: jamf.UserScopeTargets{JSSUserGroups: refs(301)},
+			want:       []string{"user:1", "user:3"},
+		},
+		{
+			name:       "excluded user and group",
+			targets:    jamf.UserScopeTargets{JSSUsers: named("john.appleseed", "jane.doe"), JSSUserGroups: refs(301)},
+			exclusions: jamf.UserScopeTargets{JSSUsers: named("john.appleseed"), JSSUserGroups: refs(301)},
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grants, err := userScopeGrants(app, assignedEntitlement, tt.all, &tt.targets, &tt.exclusions, users, userGroups)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, g := range grants {
+				got = append(got, g.GetPrincipal().GetId().GetResourceType()+":"+g.GetPrincipal().GetId().GetResource())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type buildingResourceType struct {
+	resourceType *v2.ResourceType
+	client       *jamf.Client
+}
+
+func (o *buildingResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Jamf building.
+func buildingResource(building *jamf.Building, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"building_id":   building.ID,
+		"building_name": building.Name,
+	}
+	for key, value := range map[string]string{
+		"street_address_1": building.StreetAddress1,
+		"street_address_2": building.StreetAddress2,
+		"city":             building.City,
+		"state_province":   building.StateProvince,
+		"zip_postal_code":  building.ZipPostalCode,
+		"country":          building.Country,
+	} {
+		if value != "" {
+			profile[key] = value
+		}
+	}
+
+	ret, err := rs.NewResource(
+		building.Name,
+		resourceTypeBuilding,
+		building.ID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithResourceProfile(profile),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *buildingResourceType) List(ctx context.Context, parentId *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	buildings, err := o.client.GetBuildings(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list buildings: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for i := range buildings {
+		br, err := buildingResource(&buildings[i], parentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, br)
+	}
+
+	return rv, nil, nil
+}
+
+func (o *buildingResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeManagedDevice, resourceTypeUser),
+		ent.WithDescription(fmt.Sprintf("Devices located in the %s building in Jamf, and the users assigned them", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s Building %s", resource.DisplayName, memberEntitlement)),
+	}
+
+	en := ent.NewAssignmentEntitlement(resource, memberEntitlement, assignmentOptions...)
+	rv = append(rv, en)
+
+	return rv, nil, nil
+}
+
+// Grants places every computer and mobile device whose user and location
+// details name the building in it, along with the users they're assigned to.
+func (o *buildingResourceType) Grants(ctx context.Context, resource *v2.Resource, attrs rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	rv, err := locationGrants(ctx, o.client, resource, func(location *deviceLocation) bool {
+		return location.buildingID == resource.Id.Resource
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return rv, nil, nil
+}
+
+func buildingBuilder(client *jamf.Client) *buildingResourceType {
+	return &buildingResourceType{
+		resourceType: resourceTypeBuilding,
+		client:       client,
+	}
+}
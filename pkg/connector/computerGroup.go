@@ -0,0 +1,226 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const (
+	groupTypeSmart  = "smart"
+	groupTypeStatic = "static"
+)
+
+type computerGroupResourceType struct {
+	resourceType *v2.ResourceType
+	client       *jamf.Client
+}
+
+func (g *computerGroupResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return g.resourceType
+}
+
+// Create a new connector resource for a Jamf computer group.
+func computerGroupResource(group *jamf.ComputerGroup, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"computer_group_id":   group.ID,
+		"computer_group_name": group.Name,
+		"is_smart":            group.IsSmart,
+		"group_type":          deviceGroupType(group.IsSmart),
+	}
+	if group.Site.Name != "" {
+		profile["site_id"] = group.Site.ID
+		profile["site_name"] = group.Site.Name
+	}
+
+	ret, err := rs.NewGroupResource(
+		group.Name,
+		resourceTypeComputerGroup,
+		group.ID,
+		[]rs.GroupTraitOption{rs.WithGroupProfile(profile)},
+		rs.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (g *computerGroupResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	bag, offset, pageSize, err := parseOffsetPageToken(attrs.PageToken, g.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	computerGroups, total, err := g.client.GetComputerGroupsPage(ctx, offset, pageSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list computer groups: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, computerGroup := range computerGroups {
+		cr, err := computerGroupResource(computerGroup, parentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, cr)
+	}
+
+	nextToken, err := nextOffsetPageToken(bag, offset+pageSize, total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rv, &rs.SyncOpResults{NextPageToken: nextToken}, nil
+}
+
+func (g *computerGroupResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeManagedDevice),
+		ent.WithDescription(fmt.Sprintf("Member of %s Computer Group in Jamf", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s Computer Group %s", resource.DisplayName, memberEntitlement)),
+	}
+
+	en := ent.NewAssignmentEntitlement(resource, memberEntitlement, assignmentOptions...)
+	rv = append(rv, en)
+
+	return rv, nil, nil
+}
+
+// Grants emits a member grant to the managedDevice resource of every computer
+// in the group, smart or static.
+func (g *computerGroupResourceType) Grants(ctx context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	var rv []*v2.Grant
+
+	computerGroupId, err := strconv.Atoi(resource.Id.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	group, err := g.client.GetComputerGroupDetails(ctx, computerGroupId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, computer := range group.Computers {
+		deviceId, err := rs.NewResourceID(resourceTypeManagedDevice, deviceObjectID(devicePhaseComputer, strconv.Itoa(computer.ID)))
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, grant.NewGrant(resource, memberEntitlement, deviceId))
+	}
+
+	return rv, nil, nil
+}
+
+// Grant adds a computer to a static computer group. Smart groups are refused:
+// Jamf computes their membership from the group's criteria.
+func (g *computerGroupResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	computerGroupId, computerId, err := computerGroupMembershipIDs(principal, entitlement)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := g.client.GetComputerGroupDetails(ctx, computerGroupId)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: grant computer group membership: failed to get computer group %d: %w", computerGroupId, err)
+	}
+	if group.IsSmart {
+		return nil, fmt.Errorf("jamf-connector: computer group %q is a smart group; its membership is computed by Jamf and can't be granted", group.Name)
+	}
+	if hasComputerGroupMember(group, computerId) {
+		return annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	err = g.client.AddComputerGroupMember(ctx, computerGroupId, computerId)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to add computer %d to computer group %d: %w", computerId, computerGroupId, err)
+	}
+
+	return nil, nil
+}
+
+// Revoke removes a computer from a static computer group. Smart groups are
+// refused for the same reason as in Grant.
+func (g *computerGroupResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	computerGroupId, computerId, err := computerGroupMembershipIDs(grant.Principal, grant.Entitlement)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := g.client.GetComputerGroupDetails(ctx, computerGroupId)
+	if err != nil {
+		if jamf.IsNotFoundError(err) {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		return nil, fmt.Errorf("jamf-connector: revoke computer group membership: failed to get computer group %d: %w", computerGroupId, err)
+	}
+	if group.IsSmart {
+		return nil, fmt.Errorf("jamf-connector: computer group %q is a smart group; its membership is computed by Jamf and can't be revoked", group.Name)
+	}
+	if !hasComputerGroupMember(group, computerId) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	err = g.client.RemoveComputerGroupMember(ctx, computerGroupId, computerId)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to remove computer %d from computer group %d: %w", computerId, computerGroupId, err)
+	}
+
+	return nil, nil
+}
+
+// computerGroupMembershipIDs parses the computer group ID out of entitlement
+// and the computer ID out of principal, which must be a computer's
+// managedDevice resource.
+func computerGroupMembershipIDs(principal *v2.Resource, entitlement *v2.Entitlement) (int, int, error) {
+	if principal.Id.ResourceType != resourceTypeManagedDevice.Id {
+		return 0, 0, fmt.Errorf("jamf-connector: only computers can be members of computer groups, got %s", principal.Id.ResourceType)
+	}
+	phase, computerId, err := parseDeviceObjectID(principal.Id.Resource)
+	if err != nil {
+		return 0, 0, err
+	}
+	if phase != devicePhaseComputer {
+		return 0, 0, fmt.Errorf("jamf-connector: only computers can be members of computer groups, got %s device %q", phase, principal.Id.Resource)
+	}
+
+	computerGroupId, err := strconv.Atoi(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return 0, 0, fmt.Errorf("jamf-connector: invalid computer group id %q: %w", entitlement.Resource.Id.Resource, err)
+	}
+	return computerGroupId, computerId, nil
+}
+
+func hasComputerGroupMember(group *jamf.ComputerGroup, computerId int) bool {
+	return slices.ContainsFunc(group.Computers, func(c jamf.ComputerGroupMember) bool { return c.ID == computerId })
+}
+
+func computerGroupBuilder(client *jamf.Client) *computerGroupResourceType {
+	return &computerGroupResourceType{
+		resourceType: resourceTypeComputerGroup,
+		client:       client,
+	}
+}
+
+// deviceGroupType names a device group's kind for its profile.
+func deviceGroupType(isSmart bool) string {
+	if isSmart {
+		return groupTypeSmart
+	}
+	return groupTypeStatic
+}
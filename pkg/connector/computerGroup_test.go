@@ -0,0 +1,91 @@
+package connector
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+func TestComputerGroupResource_ProfileMarksSmartOrStatic(t *testing.T) {
+	tests := []struct {
+		name     string
+		group    jamf.ComputerGroup
+		wantType string
+		wantSite bool
+	}{
+		{
+			name:     "smart",
+			group:    jamf.ComputerGroup{BaseType: jamf.BaseType{ID: 1, Name: "All Managed Macs"}, IsSmart: true},
+			wantType: groupTypeSmart,
+		},
+		{
+			name: "static with site",
+			group: jamf.ComputerGroup{
+				BaseType: jamf.BaseType{ID: 2, Name: "Finance Laptops"},
+				Site:     jamf.BaseType{ID: 1, Name: "Headquarters"},
+			},
+			wantType: groupTypeStatic,
+			wantSite: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := computerGroupResource(&tt.group, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r.Id.ResourceType != resourceTypeComputerGroup.Id || r.Id.Resource != strconv.Itoa(tt.group.ID) {
+				t.Fatalf("unexpected resource id %v", r.Id)
+			}
+
+			trait, err := rs.GetGroupTrait(r)
+			if err != nil {
+				t.Fatalf("expected a group trait: %v", err)
+			}
+			profile := trait.GetProfile().AsMap()
+			if profile["group_type"] != tt.wantType || profile["is_smart"] != tt.group.IsSmart {
+				t.Errorf("expected group_type %q and is_smart %v, got %v", tt.wantType, tt.group.IsSmart, profile)
+			}
+			if _, ok := profile["site_name"]; ok != tt.wantSite {
+				t.Errorf("expected site in profile: %v, got %v", tt.wantSite, profile)
+			}
+		})
+	}
+}
+
+// TestDeviceGroupMembers_UseDeviceObjectIDs pins the principal IDs device
+// group grants point at: they must match the IDs managedDevice syncs, or the
+// grants would dangle.
+func TestDeviceGroupMembers_UseDeviceObjectIDs(t *testing.T) {
+	if got := deviceObjectID(devicePhaseComputer, "7"); got != "computer:7" {
+		t.Errorf("unexpected computer device ID %q", got)
+	}
+	if got := deviceObjectID(devicePhaseMobile, "7"); got != "mobile:7" {
+		t.Errorf("unexpected mobile device ID %q", got)
+	}
+}
+
+func TestComputerGroupMembershipIDs(t *testing.T) {
+	groupEntitlement := &v2.Entitlement{Resource: &v2.Resource{Id: &v2.ResourceId{ResourceType: resourceTypeComputerGroup.Id, Resource: "402"}}}
+	principal := func(resourceType, id string) *v2.Resource {
+		return &v2.Resource{Id: &v2.ResourceId{ResourceType: resourceType, Resource: id}}
+	}
+
+	groupId, computerId, err := computerGroupMembershipIDs(principal(resourceTypeManagedDevice.Id, "computer:7"), groupEntitlement)
+	if err != nil || groupId != 402 || computerId != 7 {
+		t.Fatalf("expected group 402 and computer 7, got %d, %d (err %v)", groupId, computerId, err)
+	}
+
+	for _, p := range []*v2.Resource{
+		principal(resourceTypeManagedDevice.Id, "mobile:7"),
+		principal(resourceTypeManagedDevice.Id, "7"),
+		principal(resourceTypeUser.Id, "7"),
+	} {
+		if _, _, err := computerGroupMembershipIDs(p, groupEntitlement); err == nil {
+			t.Errorf("expected %s %q to be refused", p.Id.ResourceType, p.Id.Resource)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	cfg "github.com/conductorone/baton-jamf/pkg/config"
 	"github.com/conductorone/baton-jamf/pkg/jamf"
@@ -57,13 +58,104 @@ var (
 			v2.ResourceType_TRAIT_ROLE,
 		},
 	}
+	// Managed devices need the "Read Computers" and "Read Mobile Devices"
+	// privileges.
 	resourceTypeManagedDevice = &v2.ResourceType{
 		Id:          "managedDevice",
 		DisplayName: "Managed Device",
 		Traits: []v2.ResourceType_Trait{
 			v2.ResourceType_TRAIT_MANAGED_DEVICE,
 		},
-		Annotations: annotationsForManagedDeviceResourceType(),
+		Annotations: optInAnnotations(),
+	}
+	// API roles and integrations need the "Read API Roles" and "Read API
+	// Integrations" privileges.
+	resourceTypeAPIRole = &v2.ResourceType{
+		Id:          "apiRole",
+		DisplayName: "API Role",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_ROLE,
+		},
+		Annotations: optInAnnotations(),
+	}
+	// Integrations hold no entitlements themselves; their access is granted
+	// through API roles.
+	resourceTypeAPIIntegration = &v2.ResourceType{
+		Id:          "apiIntegration",
+		DisplayName: "API Integration",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_APP,
+		},
+		Annotations: optInAnnotations(&v2.SkipEntitlementsAndGrants{}),
+	}
+	// Device groups need the "Read Smart/Static Computer Groups" or "Read
+	// Smart/Static Mobile Device Groups" privileges, and their members are
+	// managedDevice resources, which are themselves opt-in.
+	resourceTypeComputerGroup = &v2.ResourceType{
+		Id:          "computerGroup",
+		DisplayName: "Computer Group",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_GROUP,
+		},
+		Annotations: optInAnnotations(),
+	}
+	resourceTypeMobileDeviceGroup = &v2.ResourceType{
+		Id:          "mobileDeviceGroup",
+		DisplayName: "Mobile Device Group",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_GROUP,
+		},
+		Annotations: optInAnnotations(),
+	}
+	// LDAP servers need the "Read LDAP Servers" privilege.
+	resourceTypeLDAPServer = &v2.ResourceType{
+		Id:          "ldapServer",
+		DisplayName: "LDAP Server",
+		Annotations: optInAnnotations(),
+	}
+	// Policies need the "Read Policies" privilege, and resolving their scope
+	// reads computer groups and the computers inventory.
+	resourceTypePolicy = &v2.ResourceType{
+		Id:          "policy",
+		DisplayName: "Policy",
+		Annotations: optInAnnotations(),
+	}
+	// Configuration profiles need the "Read macOS Configuration Profiles" or
+	// "Read iOS Configuration Profiles" privilege, and resolving their scope
+	// reads device groups and device locations.
+	resourceTypeMacOSConfigurationProfile = &v2.ResourceType{
+		Id:          "macosConfigurationProfile",
+		DisplayName: "macOS Configuration Profile",
+		Annotations: optInAnnotations(),
+	}
+	resourceTypeMobileDeviceConfigurationProfile = &v2.ResourceType{
+		Id:          "mobileDeviceConfigurationProfile",
+		DisplayName: "Mobile Device Configuration Profile",
+		Annotations: optInAnnotations(),
+	}
+	// Apps need the "Read Mac Applications", "Read Mobile Device Apps" and
+	// "Read VPP Assignments" privileges, and resolving their scope reads
+	// devices and device groups.
+	resourceTypeApp = &v2.ResourceType{
+		Id:          "app",
+		DisplayName: "App",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_APP,
+		},
+		Annotations: optInAnnotations(),
+	}
+	// Buildings and departments need the "Read Buildings" or "Read
+	// Departments" privilege, and their members are found by reading the
+	// location of every device.
+	resourceTypeBuilding = &v2.ResourceType{
+		Id:          "building",
+		DisplayName: "Building",
+		Annotations: optInAnnotations(),
+	}
+	resourceTypeDepartment = &v2.ResourceType{
+		Id:          "department",
+		DisplayName: "Department",
+		Annotations: optInAnnotations(),
 	}
 )
 
@@ -81,6 +173,10 @@ type Jamf struct {
 	// connector instance; Delete is not gated by this and works for both
 	// types regardless of the configured target.
 	accountProvisioningTarget string
+
+	// disableUserAccountsOnDelete makes deleting a userAccount disable it
+	// instead, keeping its audit history in Jamf.
+	disableUserAccountsOnDelete bool
 }
 
 // userProvisioningActive treats an empty accountProvisioningTarget as "user"
@@ -103,36 +199,57 @@ func New(ctx context.Context, cc *cfg.Jamf, opts *cli.ConnectorOpts) (connectorb
 
 	client := jamf.NewClient(
 		uhttp.NewBaseHttpClient(httpClient),
-		cc.Username,
-		cc.Password,
-		"",
+		jamf.Credentials{
+			Username:     cc.Username,
+			Password:     cc.Password,
+			ClientID:     cc.JamfClientId,
+			ClientSecret: cc.JamfClientSecret,
+		},
 		cc.InstanceUrl,
 	)
+	client.SetConcurrency(cc.Concurrency)
+	client.SetRetryPolicy(jamf.RetryPolicy{
+		MaxAttempts: cc.RetryMaxAttempts,
+		Budget:      time.Duration(cc.RetryBudgetSeconds) * time.Second,
+	})
 
-	token, err := client.CreateBearerToken(ctx, cc.Username, cc.Password)
-	if err != nil {
+	if err := client.Authenticate(ctx); err != nil {
 		return nil, nil, fmt.Errorf("jamf-connector: failed to get token: %w", err)
 	}
-	client.SetBearerToken(token)
 
 	accountProvisioningTarget := cc.CreateAccountResourceType
 	if accountProvisioningTarget == "" {
 		accountProvisioningTarget = resourceTypeUser.Id
 	}
 
-	return &Jamf{client: client, opts: opts, accountProvisioningTarget: accountProvisioningTarget}, nil, nil
+	return &Jamf{
+		client:                      client,
+		opts:                        opts,
+		accountProvisioningTarget:   accountProvisioningTarget,
+		disableUserAccountsOnDelete: cc.DisableUserAccountsOnDelete,
+	}, nil, nil
 }
 
 func (j *Jamf) Metadata(ctx context.Context) (*v2.ConnectorMetadata, error) {
 	return &v2.ConnectorMetadata{
 		DisplayName: "Jamf",
-		Description: "Connector syncing groups, users, user accounts, user groups, sites, roles, and managed devices from Jamf Pro to Baton, " +
-			"with account provisioning (create/delete) for users and user accounts",
+		Description: "Connector syncing groups, users, user accounts, user groups, sites, roles, API roles, API integrations, managed devices, computer groups, mobile device groups, LDAP servers, policies, configuration profiles, apps, buildings, and departments from Jamf Pro to Baton, " +
+			"with account provisioning (create/delete) for users and user accounts, and password rotation for user accounts",
 		AccountCreationSchema: j.accountCreationSchema(),
 	}, nil
 }
 
 func (j *Jamf) Validate(ctx context.Context) (annotations.Annotations, error) {
+	// /api/v1/auth describes the user account behind a token, so an API client
+	// has nothing to report there; listing sites is the cheapest read every
+	// sync needs anyway.
+	if j.client.UsesAPIClient() {
+		if _, err := j.client.GetSites(ctx); err != nil {
+			return nil, fmt.Errorf("jamf-connector: error validating API client: %w", err)
+		}
+		return nil, nil
+	}
+
 	tokenDetails, err := j.client.GetTokenDetails(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("jamf-connector: error fetching token details: %w", err)
@@ -151,10 +268,10 @@ func (j *Jamf) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceS
 		j.userAccountSyncer(),
 		userGroupBuilder(j.client),
 		siteBuilder(j.client),
-		roleBuilder(j.client),
+		roleBuilder(j.client, j.shouldSyncOptIn(resourceTypeAPIRole)),
 	}
 
-	// managedDevice is opt-in (see annotationsForManagedDeviceResourceType). The
+	// managedDevice is opt-in (see optInAnnotations). The
 	// SDK sync engine does not itself honor the OptInRequired annotation — with
 	// no --sync-resource-types filter it syncs every advertised type
 	// (pkg/sync/syncer.go SyncResourceTypes). To keep the type OFF by default for
@@ -162,9 +279,45 @@ func (j *Jamf) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceS
 	// has explicitly selected it. The type is still advertised (with
 	// opt_in_required: true) whenever opts is absent, i.e. when the connector
 	// emits capabilities metadata.
-	if j.shouldSyncManagedDevice() {
+	if j.shouldSyncOptIn(resourceTypeManagedDevice) {
 		syncers = append(syncers, managedDeviceBuilder(j.client))
 	}
+	// API roles and integrations are opt-in the same way.
+	if j.shouldSyncOptIn(resourceTypeAPIRole) {
+		syncers = append(syncers, apiRoleBuilder(j.client))
+	}
+	if j.shouldSyncOptIn(resourceTypeAPIIntegration) {
+		syncers = append(syncers, apiIntegrationBuilder(j.client))
+	}
+	// Computer and mobile device groups are opt-in too: they need the device
+	// group read privileges, and their members are managedDevice resources.
+	if j.shouldSyncOptIn(resourceTypeComputerGroup) {
+		syncers = append(syncers, computerGroupBuilder(j.client))
+	}
+	if j.shouldSyncOptIn(resourceTypeMobileDeviceGroup) {
+		syncers = append(syncers, mobileDeviceGroupBuilder(j.client))
+	}
+	if j.shouldSyncOptIn(resourceTypeLDAPServer) {
+		syncers = append(syncers, ldapServerBuilder(j.client))
+	}
+	if j.shouldSyncOptIn(resourceTypePolicy) {
+		syncers = append(syncers, policyBuilder(j.client))
+	}
+	if j.shouldSyncOptIn(resourceTypeMacOSConfigurationProfile) {
+		syncers = append(syncers, macOSConfigurationProfileBuilder(j.client))
+	}
+	if j.shouldSyncOptIn(resourceTypeMobileDeviceConfigurationProfile) {
+		syncers = append(syncers, mobileDeviceConfigurationProfileBuilder(j.client))
+	}
+	if j.shouldSyncOptIn(resourceTypeBuilding) {
+		syncers = append(syncers, buildingBuilder(j.client))
+	}
+	if j.shouldSyncOptIn(resourceTypeDepartment) {
+		syncers = append(syncers, departmentBuilder(j.client))
+	}
+	if j.shouldSyncOptIn(resourceTypeApp) {
+		syncers = append(syncers, appBuilder(j.client))
+	}
 
 	return syncers
 }
@@ -195,22 +348,23 @@ func (j *Jamf) userSyncer() connectorbuilder.ResourceSyncerV2 {
 // "userAccount". See provisionableUserType for why only ever registering one
 // target as an AccountManagerV2 matters.
 func (j *Jamf) userAccountSyncer() connectorbuilder.ResourceSyncerV2 {
-	base := userAccountBuilder(j.client)
+	base := userAccountBuilder(j.client, j.disableUserAccountsOnDelete)
 	if j.userAccountProvisioningActive() {
 		return &provisionableUserAccountType{base}
 	}
 	return base
 }
 
-// shouldSyncManagedDevice reports whether the opt-in managedDevice syncer should
-// be registered for this run. Metadata generation (nil opts) always advertises
-// it so baton_capabilities.json carries opt_in_required: true. A real sync
+// shouldSyncOptIn reports whether the syncer for an opt-in resource type
+// (managedDevice, apiRole, apiIntegration, computerGroup, ...) should be registered for this run.
+// Metadata generation (nil opts) always advertises it so
+// baton_capabilities.json carries opt_in_required: true. A real sync
 // registers it only when the resource-type filter explicitly names it, so an
-// empty filter ("sync everything") leaves devices — and their Jamf "Read
-// Computers" / "Read Mobile Devices" API calls — off.
-func (j *Jamf) shouldSyncManagedDevice() bool {
+// empty filter ("sync everything") leaves the type — and the Jamf API calls
+// behind it, such as "Read Computers" or "Read API Roles" — off.
+func (j *Jamf) shouldSyncOptIn(resourceType *v2.ResourceType) bool {
 	if j.opts == nil {
 		return true
 	}
-	return j.opts.SyncFilterIsExplicit() && j.opts.WillSyncResourceType(resourceTypeManagedDevice.Id)
+	return j.opts.SyncFilterIsExplicit() && j.opts.WillSyncResourceType(resourceType.Id)
 }
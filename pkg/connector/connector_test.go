@@ -82,3 +82,32 @@ func TestManagedDeviceAdvertisedForCapabilities(t *testing.T) {
 		t.Fatal("managedDevice must be advertised when emitting capabilities metadata (nil opts)")
 	}
 }
+
+// TestOptInResourceTypes proves the types added after managedDevice follow
+// it: they need Jamf privileges existing installs may lack (Read API Roles,
+// Read Computer Groups, ...), so they only sync when explicitly named.
+func TestOptInResourceTypes(t *testing.T) {
+	for _, rt := range []*v2.ResourceType{
+		resourceTypeAPIRole, resourceTypeAPIIntegration,
+		resourceTypeComputerGroup, resourceTypeMobileDeviceGroup,
+		resourceTypeLDAPServer,
+		resourceTypePolicy, resourceTypeMacOSConfigurationProfile, resourceTypeMobileDeviceConfigurationProfile,
+		resourceTypeApp, resourceTypeBuilding, resourceTypeDepartment,
+	} {
+		t.Run(rt.Id, func(t *testing.T) {
+			annos := annotations.Annotations(rt.GetAnnotations())
+			if !annos.Contains(&v2.OptInRequired{}) {
+				t.Fatalf("%s resource type must carry the OptInRequired annotation", rt.Id)
+			}
+			if syncedResourceTypeIDs(t, &cli.ConnectorOpts{})[rt.Id] {
+				t.Fatalf("%s must not be synced when no resource-type filter is set", rt.Id)
+			}
+			if !syncedResourceTypeIDs(t, &cli.ConnectorOpts{SyncResourceTypeIDs: []string{rt.Id}})[rt.Id] {
+				t.Fatalf("%s must sync when explicitly opted in via the resource-type filter", rt.Id)
+			}
+			if !syncedResourceTypeIDs(t, nil)[rt.Id] {
+				t.Fatalf("%s must be advertised when emitting capabilities metadata (nil opts)", rt.Id)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type departmentResourceType struct {
+	resourceType *v2.ResourceType
+	client       *jamf.Client
+}
+
+func (o *departmentResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Jamf department.
+func departmentResource(department *jamf.Department, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"department_id":   department.ID,
+		"department_name": department.Name,
+	}
+
+	ret, err := rs.NewResource(
+		department.Name,
+		resourceTypeDepartment,
+		department.ID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithResourceProfile(profile),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *departmentResourceType) List(ctx context.Context, parentId *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	departments, err := o.client.GetDepartments(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list departments: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for i := range departments {
+		dr, err := departmentResource(&departments[i], parentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, dr)
+	}
+
+	return rv, nil, nil
+}
+
+func (o *departmentResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeManagedDevice, resourceTypeUser),
+		ent.WithDescription(fmt.Sprintf("Devices in the %s department in Jamf, and the users assigned them", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s Department %s", resource.DisplayName, memberEntitlement)),
+	}
+
+	en := ent.NewAssignmentEntitlement(resource, memberEntitlement, assignmentOptions...)
+	rv = append(rv, en)
+
+	return rv, nil, nil
+}
+
+// Grants places every computer and mobile device whose user and location
+// details name the department in it, along with the users they're assigned
+// to.
+func (o *departmentResourceType) Grants(ctx context.Context, resource *v2.Resource, attrs rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	rv, err := locationGrants(ctx, o.client, resource, func(location *deviceLocation) bool {
+		return location.departmentID == resource.Id.Resource
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return rv, nil, nil
+}
+
+func departmentBuilder(client *jamf.Client) *departmentResourceType {
+	return &departmentResourceType{
+		resourceType: resourceTypeDepartment,
+		client:       client,
+	}
+}
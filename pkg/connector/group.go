@@ -3,11 +3,13 @@ package connector
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"time"
 
 	"github.com/conductorone/baton-jamf/pkg/jamf"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
 	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
@@ -30,6 +32,7 @@ func groupResource(group *jamf.Group, parentResourceID *v2.ResourceId) (*v2.Reso
 		"group_id":   group.ID,
 		"group_name": group.Name,
 	}
+	addAccountSource(profile, group.IsLDAP(), group.LDAPServer)
 
 	ret, err := rs.NewGroupResource(
 		group.Name,
@@ -47,7 +50,13 @@ func groupResource(group *jamf.Group, parentResourceID *v2.ResourceId) (*v2.Reso
 }
 
 func (g *groupResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
-	_, groups, err := g.client.GetAccounts(ctx)
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	bag, offset, pageSize, err := parseOffsetPageToken(attrs.PageToken, g.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groups, total, err := g.client.GetGroupsPage(ctx, offset, pageSize)
 	if err != nil {
 		return nil, nil, fmt.Errorf("jamf-connector: failed to list accounts: %w", err)
 	}
@@ -61,7 +70,13 @@ func (g *groupResourceType) List(ctx context.Context, parentId *v2.ResourceId, a
 		}
 		rv = append(rv, gr)
 	}
-	return rv, nil, nil
+
+	nextToken, err := nextOffsetPageToken(bag, offset+pageSize, total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rv, &rs.SyncOpResults{NextPageToken: nextToken}, nil
 }
 
 func (g *groupResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
@@ -127,6 +142,96 @@ func (g *groupResourceType) Grants(ctx context.Context, resource *v2.Resource, a
 	return rv, nil, nil
 }
 
+// Grant adds a userAccount to a Jamf admin group. Membership is written
+// through the account (PUT /JSSResource/accounts/userid/{id}) rather than the
+// group: the group endpoint's members list is unreliable (see the HACK in
+// Grants), and a read-modify-write against it could silently drop members.
+func (g *groupResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	if principal.Id.ResourceType != resourceTypeUserAccount.Id {
+		return nil, fmt.Errorf("jamf-connector: only user accounts can be added to groups, got %s", principal.Id.ResourceType)
+	}
+
+	groupId, err := strconv.Atoi(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: grant group membership: invalid group id %q: %w", entitlement.Resource.Id.Resource, err)
+	}
+	accountId, err := strconv.Atoi(principal.Id.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: grant group membership: invalid account id %q: %w", principal.Id.Resource, err)
+	}
+
+	account, err := g.client.GetUserAccountDetails(ctx, accountId)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: grant group membership: failed to get account %d: %w", accountId, err)
+	}
+
+	groupIds := accountGroupIDs(account)
+	if slices.Contains(groupIds, groupId) {
+		return annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	err = g.client.UpdateUserAccount(ctx, accountId, jamf.UserAccountUpdateBody{
+		Groups: jamf.NewAccountGroups(append(groupIds, groupId)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to add account %d to group %d: %w", accountId, groupId, err)
+	}
+
+	return nil, nil
+}
+
+// Revoke removes a userAccount from a Jamf admin group. See Grant for why
+// the update goes through the account.
+func (g *groupResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	principal := grant.Principal
+	entitlement := grant.Entitlement
+	if principal.Id.ResourceType != resourceTypeUserAccount.Id {
+		return nil, fmt.Errorf("jamf-connector: only user accounts can be removed from groups, got %s", principal.Id.ResourceType)
+	}
+
+	groupId, err := strconv.Atoi(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: revoke group membership: invalid group id %q: %w", entitlement.Resource.Id.Resource, err)
+	}
+	accountId, err := strconv.Atoi(principal.Id.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: revoke group membership: invalid account id %q: %w", principal.Id.Resource, err)
+	}
+
+	account, err := g.client.GetUserAccountDetails(ctx, accountId)
+	if err != nil {
+		if jamf.IsNotFoundError(err) {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		return nil, fmt.Errorf("jamf-connector: revoke group membership: failed to get account %d: %w", accountId, err)
+	}
+
+	groupIds := accountGroupIDs(account)
+	if !slices.Contains(groupIds, groupId) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	err = g.client.UpdateUserAccount(ctx, accountId, jamf.UserAccountUpdateBody{
+		Groups: jamf.NewAccountGroups(slices.DeleteFunc(groupIds, func(id int) bool { return id == groupId })),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to remove account %d from group %d: %w", accountId, groupId, err)
+	}
+
+	return nil, nil
+}
+
+// accountGroupIDs returns the IDs of the admin groups an account belongs to.
+func accountGroupIDs(account *jamf.UserAccount) []int {
+	ids := make([]int, 0, len(account.Groups))
+	for _, group := range account.Groups {
+		ids = append(ids, group.ID)
+	}
+	return ids
+}
+
 func groupBuilder(client *jamf.Client) *groupResourceType {
 	return &groupResourceType{
 		resourceType: resourceTypeGroup,
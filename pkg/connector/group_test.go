@@ -0,0 +1,150 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestClient returns a Jamf client talking to handler, which a token
+// endpoint is put in front of.
+func newTestClient(t *testing.T, handler http.Handler) *jamf.Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/auth/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jamf.TokenResponse{Token: "test-token", Expires: time.Now().Add(time.Hour).Format(time.RFC3339)})
+	})
+	mux.Handle("/", handler)
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	httpClient, err := uhttp.NewClient(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return jamf.NewClient(uhttp.NewBaseHttpClient(httpClient), jamf.Credentials{Username: "test-user", Password: "test-pass"}, ts.URL)
+}
+
+// hasAnnotation reports whether annos carries an annotation of msg's type.
+func hasAnnotation(annos annotations.Annotations, msg proto.Message) bool {
+	return annos.Contains(msg)
+}
+
+// fakeAccounts serves GET and PUT on /JSSResource/accounts/userid/{id} from
+// the admin groups of each account, and counts the PUTs.
+type fakeAccounts struct {
+	mu     sync.Mutex
+	groups map[int][]int
+	puts   int
+}
+
+func (f *fakeAccounts) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/JSSResource/accounts/userid/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	groupIds, ok := f.groups[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		account := jamf.UserAccount{BaseType: jamf.BaseType{ID: id, Name: "admin" + strconv.Itoa(id)}}
+		for _, groupId := range groupIds {
+			account.Groups = append(account.Groups, jamf.BaseType{ID: groupId})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jamf.UserAccountResponse{UserAccount: account})
+	case http.MethodPut:
+		var body jamf.UserAccountUpdateBody
+		if err := xml.NewDecoder(r.Body).Decode(&body); err != nil || body.Groups == nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+		f.groups[id] = f.groups[id][:0]
+		for _, group := range body.Groups.Groups {
+			f.groups[id] = append(f.groups[id], group.ID)
+		}
+		f.puts++
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestGroupMembershipGrantAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	accounts := &fakeAccounts{groups: map[int][]int{101: {201}}}
+	g := groupBuilder(newTestClient(t, accounts))
+
+	account := func(id string) *v2.Resource {
+		return &v2.Resource{Id: &v2.ResourceId{ResourceType: resourceTypeUserAccount.Id, Resource: id}}
+	}
+	entitlement := &v2.Entitlement{Resource: &v2.Resource{Id: &v2.ResourceId{ResourceType: resourceTypeGroup.Id, Resource: "202"}}}
+	grant := &v2.Grant{Principal: account("101"), Entitlement: entitlement}
+
+	annos, err := g.Grant(ctx, account("101"), entitlement)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hasAnnotation(annos, &v2.GrantAlreadyExists{}) {
+		t.Fatal("expected the first grant to add the account")
+	}
+	if !slices.Equal(accounts.groups[101], []int{201, 202}) {
+		t.Fatalf("expected the account to keep group 201 and join 202, got %v", accounts.groups[101])
+	}
+
+	// The second grant has to see the first one's update rather than a
+	// response cached before it.
+	annos, err = g.Grant(ctx, account("101"), entitlement)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasAnnotation(annos, &v2.GrantAlreadyExists{}) || accounts.puts != 1 {
+		t.Fatalf("expected the second grant to be reported as already existing without an update, got %d updates", accounts.puts)
+	}
+
+	annos, err = g.Revoke(ctx, grant)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) || !slices.Equal(accounts.groups[101], []int{201}) {
+		t.Fatalf("expected the revoke to remove only group 202, got %v", accounts.groups[101])
+	}
+
+	annos, err = g.Revoke(ctx, grant)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) || accounts.puts != 2 {
+		t.Fatalf("expected the second revoke to be reported as already revoked without an update, got %d updates", accounts.puts)
+	}
+
+	annos, err = g.Revoke(ctx, &v2.Grant{Principal: account("999"), Entitlement: entitlement})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) {
+		t.Error("expected revoking from a missing account to be reported as already revoked")
+	}
+}
@@ -1,10 +1,62 @@
 package connector
 
 import (
+	"fmt"
+	"strconv"
+
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"google.golang.org/protobuf/proto"
 )
 
+// defaultListPageSize is how many Classic API objects a List call returns
+// when the SDK does not supply a page size. Most of them cost a detail
+// request each, so a page is also the unit of work a resumed sync redoes.
+const defaultListPageSize = 100
+
+// parseOffsetPageToken decodes a page token produced by nextOffsetPageToken
+// into its bag, the offset into the listing the page starts at, and the page
+// size. An empty token starts at offset 0.
+func parseOffsetPageToken(token pagination.Token, resourceTypeID string) (*pagination.Bag, int, int, error) {
+	bag := &pagination.Bag{}
+	if err := bag.Unmarshal(token.Token); err != nil {
+		return nil, 0, 0, fmt.Errorf("jamf-connector: failed to parse %s page token: %w", resourceTypeID, err)
+	}
+	if bag.Current() == nil {
+		bag.Push(pagination.PageState{ResourceTypeID: resourceTypeID})
+	}
+
+	offset := 0
+	if pageToken := bag.PageToken(); pageToken != "" {
+		var err error
+		offset, err = strconv.Atoi(pageToken)
+		if err != nil || offset < 0 {
+			return nil, 0, 0, fmt.Errorf("jamf-connector: invalid %s page offset %q", resourceTypeID, pageToken)
+		}
+	}
+
+	pageSize := token.Size
+	if pageSize <= 0 {
+		pageSize = defaultListPageSize
+	}
+
+	return bag, offset, pageSize, nil
+}
+
+// nextOffsetPageToken returns the token for the page starting at next, or an
+// empty token once next reaches total and the listing is exhausted.
+func nextOffsetPageToken(bag *pagination.Bag, next, total int) (string, error) {
+	if next >= total {
+		bag.Pop()
+		return bag.Marshal()
+	}
+	if err := bag.Next(strconv.Itoa(next)); err != nil {
+		return "", err
+	}
+	return bag.Marshal()
+}
+
 // stringSliceFromProfile reads a repeated-string field out of an account
 // creation profile map (as produced by structpb's AsMap — a []interface{} of
 // strings), tolerating an absent or wrongly-typed field by returning nil.
@@ -28,15 +80,19 @@ func annotationsForUserResourceType() annotations.Annotations {
 	return annos
 }
 
-// annotationsForManagedDeviceResourceType marks the managedDevice resource type
-// as opt-in. The OptInRequired annotation is surfaced in baton_capabilities.json
-// so the C1 platform leaves device syncing OFF by default; existing installs
-// whose Jamf API role lacks "Read Computers" / "Read Mobile Devices" are
-// therefore unaffected until an operator explicitly enables the type. See the
-// registration gate in (*Jamf).ResourceSyncers for the connector-side
-// enforcement that keeps local/CLI syncs off by default too.
-func annotationsForManagedDeviceResourceType() annotations.Annotations {
+// optInAnnotations marks a resource type as opt-in, adding extra to the
+// OptInRequired annotation. The annotation is surfaced in
+// baton_capabilities.json so the C1 platform leaves the type OFF by default;
+// existing installs whose Jamf API role lacks the privileges the type needs
+// (noted on each resource type definition) are therefore unaffected until an
+// operator explicitly enables it. See the registration gate in
+// (*Jamf).ResourceSyncers for the connector-side enforcement that keeps
+// local/CLI syncs off by default too.
+func optInAnnotations(extra ...proto.Message) annotations.Annotations {
 	annos := annotations.Annotations{}
 	annos.Update(&v2.OptInRequired{})
+	for _, msg := range extra {
+		annos.Update(msg)
+	}
 	return annos
 }
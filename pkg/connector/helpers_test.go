@@ -0,0 +1,73 @@
+package connector
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// resourceIDArgs returns action arguments whose arg names the resource of
+// resourceType, as a resource ID argument is passed to an action.
+func resourceIDArgs(t *testing.T, arg, resourceType, resource string) *structpb.Struct {
+	t.Helper()
+	s, err := structpb.NewStruct(map[string]interface{}{
+		arg: map[string]interface{}{"resource_type_id": resourceType, "resource_id": resource},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return s
+}
+
+// TestOffsetPageToken walks a listing of 5 objects two at a time and checks
+// the tokens step through offsets 0, 2 and 4 and then end the listing.
+func TestOffsetPageToken(t *testing.T) {
+	var (
+		token   string
+		offsets []int
+	)
+	for range 10 {
+		bag, offset, pageSize, err := parseOffsetPageToken(pagination.Token{Token: token, Size: 2}, resourceTypeUser.Id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		offsets = append(offsets, offset)
+
+		token, err = nextOffsetPageToken(bag, offset+pageSize, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token == "" {
+			break
+		}
+	}
+
+	if token != "" {
+		t.Fatal("expected the listing to end")
+	}
+	if want := []int{0, 2, 4}; !slices.Equal(offsets, want) {
+		t.Errorf("expected offsets %v, got %v", want, offsets)
+	}
+}
+
+func TestOffsetPageToken_DefaultsAndInvalid(t *testing.T) {
+	_, offset, pageSize, err := parseOffsetPageToken(pagination.Token{}, resourceTypeSite.Id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if offset != 0 || pageSize != defaultListPageSize {
+		t.Errorf("expected offset 0 and page size %d, got %d and %d", defaultListPageSize, offset, pageSize)
+	}
+
+	bag := &pagination.Bag{}
+	bag.Push(pagination.PageState{ResourceTypeID: resourceTypeSite.Id, Token: "not-a-number"})
+	token, err := bag.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, _, err := parseOffsetPageToken(pagination.Token{Token: token}, resourceTypeSite.Id); err == nil {
+		t.Error("expected an invalid offset to be rejected")
+	}
+}
@@ -0,0 +1,144 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// Values of the "source" profile field of admin accounts and groups.
+const (
+	accountSourceLocal = "local"
+	accountSourceLDAP  = "ldap"
+)
+
+type ldapServerResourceType struct {
+	resourceType *v2.ResourceType
+	client       *jamf.Client
+}
+
+func (o *ldapServerResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Jamf LDAP server.
+func ldapServerResource(server *jamf.LDAPServer, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	connection := server.Connection
+	profile := map[string]interface{}{
+		"ldap_server_id":   connection.ID,
+		"ldap_server_name": connection.Name,
+		"hostname":         connection.Hostname,
+		"server_type":      connection.ServerType,
+		"port":             connection.Port,
+		"use_ssl":          connection.UseSSL,
+	}
+
+	ret, err := rs.NewResource(
+		connection.Name,
+		resourceTypeLDAPServer,
+		connection.ID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithResourceProfile(profile),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *ldapServerResourceType) List(ctx context.Context, parentId *v2.ResourceId, _ rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	servers, err := o.client.GetLDAPServers(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list LDAP servers: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, server := range servers {
+		sr, err := ldapServerResource(server, parentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, sr)
+	}
+
+	return rv, nil, nil
+}
+
+func (o *ldapServerResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeUserAccount, resourceTypeGroup),
+		ent.WithDescription(fmt.Sprintf("User accounts and groups backed by the %s LDAP server in Jamf", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s LDAP Server %s", resource.DisplayName, memberEntitlement)),
+	}
+
+	en := ent.NewAssignmentEntitlement(resource, memberEntitlement, assignmentOptions...)
+	rv = append(rv, en)
+
+	return rv, nil, nil
+}
+
+// Grants links the LDAP server to every admin account and group backed by
+// it. An LDAP group's access reaches whoever is in the directory group it
+// maps, which Jamf itself never lists.
+func (o *ldapServerResourceType) Grants(ctx context.Context, resource *v2.Resource, attrs rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	userAccounts, groups, err := o.client.GetAccounts(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list accounts: %w", err)
+	}
+
+	var rv []*v2.Grant
+	for _, userAccount := range userAccounts {
+		if strconv.Itoa(userAccount.LDAPServer.ID) != resource.Id.Resource {
+			continue
+		}
+		uar, err := userAccountResource(userAccount, resource.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, grant.NewGrant(resource, memberEntitlement, uar.Id))
+	}
+
+	for _, group := range groups {
+		if strconv.Itoa(group.LDAPServer.ID) != resource.Id.Resource {
+			continue
+		}
+		gr, err := groupResource(group, resource.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, grant.NewGrant(resource, memberEntitlement, gr.Id))
+	}
+
+	return rv, nil, nil
+}
+
+func ldapServerBuilder(client *jamf.Client) *ldapServerResourceType {
+	return &ldapServerResourceType{
+		resourceType: resourceTypeLDAPServer,
+		client:       client,
+	}
+}
+
+// addAccountSource records in an admin account or group profile whether it
+// is local to Jamf or backed by an LDAP server, and which one.
+func addAccountSource(profile map[string]interface{}, isLDAP bool, ldapServer jamf.BaseType) {
+	if !isLDAP {
+		profile["source"] = accountSourceLocal
+		return
+	}
+	profile["source"] = accountSourceLDAP
+	if ldapServer.ID > 0 {
+		profile["ldap_server_id"] = ldapServer.ID
+		profile["ldap_server_name"] = ldapServer.Name
+	}
+}
@@ -0,0 +1,38 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+)
+
+func TestAccountProfilesRecordSource(t *testing.T) {
+	corpAD := jamf.BaseType{ID: 1, Name: "corp-ad"}
+
+	local, err := userAccountResource(&jamf.UserAccount{BaseType: jamf.BaseType{ID: 101, Name: "admin1"}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := local.GetProfile().AsMap(); got["source"] != accountSourceLocal || got["ldap_server_id"] != nil {
+		t.Errorf("expected a local account without an LDAP server, got %v", got)
+	}
+
+	directory, err := userAccountResource(&jamf.UserAccount{
+		BaseType: jamf.BaseType{ID: 104, Name: "admin4"}, DirectoryUser: true, LDAPServer: corpAD,
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := directory.GetProfile().AsMap(); got["source"] != accountSourceLDAP || got["ldap_server_name"] != "corp-ad" {
+		t.Errorf("expected an LDAP account on corp-ad, got %v", got)
+	}
+
+	group, err := groupResource(&jamf.Group{BaseType: jamf.BaseType{ID: 204, Name: "group-directory-auditors"}, LDAPServer: corpAD}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// structpb stores numbers as float64.
+	if got := group.GetProfile().AsMap(); got["source"] != accountSourceLDAP || got["ldap_server_id"] != float64(1) {
+		t.Errorf("expected an LDAP group on server 1, got %v", got)
+	}
+}
@@ -0,0 +1,121 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// deviceLocation places a device in a building and a department, and records
+// who it's assigned to. Any of them may be empty.
+type deviceLocation struct {
+	id           int
+	buildingID   string
+	departmentID string
+	username     string
+	email        string
+}
+
+// deviceLocations returns the location of every computer or mobile device.
+func deviceLocations(ctx context.Context, client *jamf.Client, phase string) ([]deviceLocation, error) {
+	var rv []deviceLocation
+	if phase == devicePhaseMobile {
+		devices, err := client.GetMobileDeviceLocations(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to list mobile device locations: %w", err)
+		}
+		for i := range devices {
+			id, err := strconv.Atoi(devices[i].MobileDeviceID)
+			if err != nil {
+				continue
+			}
+			location := deviceLocation{id: id}
+			if ul := devices[i].UserAndLocation; ul != nil {
+				location.buildingID, location.departmentID = ul.BuildingID, ul.DepartmentID
+				location.username, location.email = ul.Username, ul.EmailAddress
+			}
+			rv = append(rv, location)
+		}
+		return rv, nil
+	}
+
+	computers, err := client.GetComputerLocations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to list computer locations: %w", err)
+	}
+	for i := range computers {
+		id, err := strconv.Atoi(computers[i].ID)
+		if err != nil {
+			continue
+		}
+		location := deviceLocation{id: id}
+		if ul := computers[i].UserAndLocation; ul != nil {
+			location.buildingID, location.departmentID = ul.BuildingID, ul.DepartmentID
+			location.username, location.email = ul.Username, ul.EmailAddr()
+		}
+		rv = append(rv, location)
+	}
+	return rv, nil
+}
+
+// locationGrants builds the grants of a building or department's member
+// entitlement. located reports whether a device is in it.
+func locationGrants(ctx context.Context, client *jamf.Client, resource *v2.Resource, located func(location *deviceLocation) bool) ([]*v2.Grant, error) {
+	users, err := client.GetUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to list users: %w", err)
+	}
+
+	locations := make(map[string][]deviceLocation)
+	for _, phase := range []string{devicePhaseComputer, devicePhaseMobile} {
+		locations[phase], err = deviceLocations(ctx, client, phase)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return locatedMemberGrants(resource, locations, located, newUserIndex(users))
+}
+
+// locatedMemberGrants grants resource's member entitlement to every device
+// located in it, and once to every synced user assigned one of those
+// devices. Assignees that aren't synced Jamf users are skipped; the device's
+// own assigned grant already matches them to a directory identity.
+func locatedMemberGrants(
+	resource *v2.Resource,
+	locations map[string][]deviceLocation,
+	located func(location *deviceLocation) bool,
+	userIndex map[string]*v2.ResourceId,
+) ([]*v2.Grant, error) {
+	var (
+		rv      []*v2.Grant
+		granted = make(map[string]bool)
+	)
+	for _, phase := range []string{devicePhaseComputer, devicePhaseMobile} {
+		for i := range locations[phase] {
+			location := &locations[phase][i]
+			if !located(location) {
+				continue
+			}
+
+			deviceId, err := rs.NewResourceID(resourceTypeManagedDevice, deviceObjectID(phase, strconv.Itoa(location.id)))
+			if err != nil {
+				return nil, err
+			}
+			rv = append(rv, grant.NewGrant(resource, memberEntitlement, deviceId))
+
+			userId, ok := resolveUser(userIndex, location.username, location.email)
+			if !ok || granted[userId.GetResource()] {
+				continue
+			}
+			granted[userId.GetResource()] = true
+			rv = append(rv, grant.NewGrant(resource, memberEntitlement, userId))
+		}
+	}
+	return rv, nil
+}
@@ -0,0 +1,44 @@
+package connector
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+)
+
+func TestLocatedMemberGrants(t *testing.T) {
+	building, err := buildingResource(&jamf.Building{ID: "1", Name: "HQ Tower"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	userIndex := newUserIndex([]*jamf.User{{BaseType: jamf.BaseType{ID: 1, Name: "john.appleseed"}}})
+	locations := map[string][]deviceLocation{
+		devicePhaseComputer: {
+			{id: 1, buildingID: "1", username: "john.appleseed"},
+			{id: 2, buildingID: "2", username: "jane.doe"},
+			{id: 3, buildingID: "1", username: "contractor"},
+		},
+		devicePhaseMobile: {
+			{id: 1, buildingID: "1", username: "John.Appleseed"},
+		},
+	}
+
+	grants, err := locatedMemberGrants(building, locations, func(location *deviceLocation) bool {
+		return location.buildingID == "1"
+	}, userIndex)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, g := range grants {
+		got = append(got, g.GetPrincipal().GetId().GetResourceType()+":"+g.GetPrincipal().GetId().GetResource())
+	}
+	// The user is granted once for two devices, and the unsynced assignee
+	// not at all.
+	want := []string{"managedDevice:computer:1", "user:1", "managedDevice:computer:3", "managedDevice:mobile:1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
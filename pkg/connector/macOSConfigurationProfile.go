@@ -0,0 +1,137 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type macOSConfigurationProfileResourceType struct {
+	resourceType *v2.ResourceType
+	client       *jamf.Client
+}
+
+func (o *macOSConfigurationProfileResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// configurationProfileProfile returns the resource profile fields macOS and
+// mobile device configuration profiles share.
+func configurationProfileProfile(general *jamf.ConfigurationProfileGeneral) map[string]interface{} {
+	profile := map[string]interface{}{
+		"configuration_profile_id":   general.ID,
+		"configuration_profile_name": general.Name,
+	}
+	for key, value := range map[string]string{
+		"description":         general.Description,
+		"level":               general.Level,
+		"distribution_method": general.DistributionMethod,
+		"uuid":                general.UUID,
+		"category":            general.Category.Name,
+	} {
+		if value != "" {
+			profile[key] = value
+		}
+	}
+	if general.Site.Name != "" {
+		profile["site_id"] = general.Site.ID
+		profile["site_name"] = general.Site.Name
+	}
+	return profile
+}
+
+// Create a new connector resource for a Jamf macOS configuration profile.
+func macOSConfigurationProfileResource(configurationProfile *jamf.MacOSConfigurationProfile, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := configurationProfileProfile(&configurationProfile.General)
+	profile["all_computers"] = configurationProfile.Scope.AllComputers
+
+	ret, err := rs.NewResource(
+		configurationProfile.General.Name,
+		resourceTypeMacOSConfigurationProfile,
+		configurationProfile.General.ID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithResourceProfile(profile),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *macOSConfigurationProfileResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	bag, offset, pageSize, err := parseOffsetPageToken(attrs.PageToken, o.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configurationProfiles, total, err := o.client.GetMacOSConfigurationProfilesPage(ctx, offset, pageSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list macOS configuration profiles: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, configurationProfile := range configurationProfiles {
+		pr, err := macOSConfigurationProfileResource(configurationProfile, parentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, pr)
+	}
+
+	nextToken, err := nextOffsetPageToken(bag, offset+pageSize, total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rv, &rs.SyncOpResults{NextPageToken: nextToken}, nil
+}
+
+func (o *macOSConfigurationProfileResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeManagedDevice, resourceTypeComputerGroup, resourceTypeBuilding, resourceTypeDepartment),
+		ent.WithDescription(fmt.Sprintf("In scope of the %s macOS configuration profile in Jamf", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s Configuration Profile %s", resource.DisplayName, scopedEntitlement)),
+	}
+
+	en := ent.NewAssignmentEntitlement(resource, scopedEntitlement, assignmentOptions...)
+	rv = append(rv, en)
+
+	return rv, nil, nil
+}
+
+// Grants emits the profile's scope, with exclusions applied (see
+// scopeGrants).
+func (o *macOSConfigurationProfileResourceType) Grants(ctx context.Context, resource *v2.Resource, attrs rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	profileId, err := strconv.Atoi(resource.Id.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configurationProfile, err := o.client.GetMacOSConfigurationProfileDetails(ctx, profileId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rv, err := scopeGrants(ctx, o.client, resource, scopedEntitlement, computerScope(&configurationProfile.Scope))
+	if err != nil {
+		return nil, nil, err
+	}
+	return rv, nil, nil
+}
+
+func macOSConfigurationProfileBuilder(client *jamf.Client) *macOSConfigurationProfileResourceType {
+	return &macOSConfigurationProfileResourceType{
+		resourceType: resourceTypeMacOSConfigurationProfile,
+		client:       client,
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/conductorone/baton-jamf/pkg/jamf"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
 	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
@@ -29,6 +30,10 @@ const (
 	// assignedEntitlement links a device to the user it is assigned to.
 	assignedEntitlement = "assigned"
 
+	// localAdminEntitlement links a computer to the users holding a local
+	// macOS admin account on it.
+	localAdminEntitlement = "local_admin"
+
 	// ExternalResourceMatch key names used when a device's assignee can't be
 	// resolved to a synced Jamf user.
 	matchKeyEmail    = "email"
@@ -51,9 +56,13 @@ type managedDeviceResourceType struct {
 	// deviceOwners maps a device resource id to the assignee identity recorded
 	// while listing devices, so Entitlements/Grants can emit the device->user
 	// grant without carrying it on the resource.
+	//
+	// localAdmins likewise maps a computer resource id to the usernames of its
+	// local admin accounts.
 	mu           sync.Mutex
 	userIndex    map[string]*v2.ResourceId
 	deviceOwners map[string]deviceOwner
+	localAdmins  map[string][]string
 }
 
 func (d *managedDeviceResourceType) ResourceType(_ context.Context) *v2.ResourceType {
@@ -94,6 +103,7 @@ func (d *managedDeviceResourceType) List(ctx context.Context, parentId *v2.Resou
 			if c.UserAndLocation != nil {
 				d.recordDeviceOwner(r, c.UserAndLocation.Username, c.UserAndLocation.EmailAddr())
 			}
+			d.recordLocalAdmins(r, c.LocalUserAccounts)
 			resources = append(resources, r)
 		}
 		if hasMorePages(seen, pageSize, resp.TotalCount, len(resp.Results)) {
@@ -141,30 +151,40 @@ func (d *managedDeviceResourceType) List(ctx context.Context, parentId *v2.Resou
 	return resources, &rs.SyncOpResults{NextPageToken: nextToken}, nil
 }
 
-// Entitlements exposes the "assigned" assignment entitlement, but only for
-// devices that actually report an assignee, so unassigned assets stay clean.
+// Entitlements exposes the "assigned" assignment entitlement on every device,
+// so an unassigned device can be granted to a user. Computers with local
+// admin accounts also expose "local_admin".
 func (d *managedDeviceResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
-	username, email := d.deviceAssignee(resource)
-	if username == "" && email == "" {
-		return nil, nil, nil
-	}
-
-	opts := []ent.EntitlementOption{
-		ent.WithGrantableTo(resourceTypeUser),
-		ent.WithDescription(fmt.Sprintf("Assigned user of the %s device", resource.DisplayName)),
-		ent.WithDisplayName(fmt.Sprintf("%s device %s", resource.DisplayName, assignedEntitlement)),
+	rv := []*v2.Entitlement{
+		ent.NewAssignmentEntitlement(resource, assignedEntitlement,
+			ent.WithGrantableTo(resourceTypeUser),
+			ent.WithDescription(fmt.Sprintf("Assigned user of the %s device", resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("%s device %s", resource.DisplayName, assignedEntitlement)),
+		),
+	}
+
+	if len(d.deviceLocalAdmins(resource)) > 0 {
+		opts := []ent.EntitlementOption{
+			ent.WithGrantableTo(resourceTypeUser),
+			ent.WithDescription(fmt.Sprintf("Local macOS admin account on the %s computer", resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("%s device %s", resource.DisplayName, localAdminEntitlement)),
+		}
+		rv = append(rv, ent.NewPermissionEntitlement(resource, localAdminEntitlement, opts...))
 	}
 
-	return []*v2.Entitlement{ent.NewAssignmentEntitlement(resource, assignedEntitlement, opts...)}, nil, nil
+	return rv, nil, nil
 }
 
-// Grants emits the device->user link as a grant on the "assigned" entitlement.
-// When the assignee is a synced Jamf user it grants that user directly; otherwise
+// Grants emits the device->user link as a grant on the "assigned" entitlement,
+// and a grant on "local_admin" for every local admin account of a computer.
+// When the user is a synced Jamf user it grants that user directly; otherwise
 // it annotates the grant with an ExternalResourceMatch so the platform can bind it
 // to a directory identity from an external source.
-func (d *managedDeviceResourceType) Grants(ctx context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+func (d *managedDeviceResourceType) Grants(ctx context.Context, resource *v2.Resource, attrs rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
 	username, email := d.deviceAssignee(resource)
-	if username == "" && email == "" {
+	admins := d.deviceLocalAdmins(resource)
+	if username == "" && email == "" && len(admins) == 0 {
 		return nil, nil, nil
 	}
 
@@ -177,7 +197,148 @@ func (d *managedDeviceResourceType) Grants(ctx context.Context, resource *v2.Res
 	if err != nil {
 		return nil, nil, err
 	}
-	return grants, nil, nil
+	adminGrants, err := localAdminGrants(resource, admins, userIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(grants, adminGrants...), nil, nil
+}
+
+// Grant assigns the device to a synced Jamf user, replacing its current
+// assignee. Jamf records the assignment as the device's user and location
+// username, real name and email.
+func (d *managedDeviceResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	if entitlement.Slug != assignedEntitlement {
+		return nil, fmt.Errorf("jamf-connector: only the %s device entitlement can be granted, got %q", assignedEntitlement, entitlement.Slug)
+	}
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		return nil, fmt.Errorf("jamf-connector: devices can only be assigned to users, got %s", principal.Id.ResourceType)
+	}
+	userId, err := strconv.Atoi(principal.Id.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: devices can only be assigned to synced Jamf users, got %q", principal.Id.Resource)
+	}
+	phase, deviceId, err := parseDeviceObjectID(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := d.client.GetUserDetails(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: assign device: failed to get user %d: %w", userId, err)
+	}
+	username, email, err := d.getDeviceAssignee(ctx, phase, deviceId)
+	if err != nil {
+		return nil, err
+	}
+	if isAssignedTo(username, email, user) {
+		return annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	email = user.Email
+	if email == "" {
+		email = user.EmailAddress
+	}
+	err = d.setDeviceAssignee(ctx, phase, deviceId, user.Name, user.FullName, email)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to assign %s %d to user %d: %w", phase, deviceId, userId, err)
+	}
+	d.recordDeviceOwner(entitlement.Resource, user.Name, email)
+
+	return nil, nil
+}
+
+// Revoke clears the device's assignee, provided it's still the grant's
+// principal. The principal may be an unsynced assignee matched by username or
+// email (see deviceGrants).
+func (d *managedDeviceResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	principal, entitlement := grant.Principal, grant.Entitlement
+	if entitlement.Slug != assignedEntitlement {
+		return nil, fmt.Errorf("jamf-connector: only the %s device entitlement can be revoked, got %q", assignedEntitlement, entitlement.Slug)
+	}
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		return nil, fmt.Errorf("jamf-connector: devices can only be assigned to users, got %s", principal.Id.ResourceType)
+	}
+	phase, deviceId, err := parseDeviceObjectID(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	username, email, err := d.getDeviceAssignee(ctx, phase, deviceId)
+	if err != nil {
+		if jamf.IsNotFoundError(err) {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		return nil, err
+	}
+
+	var user *jamf.User
+	if userId, err := strconv.Atoi(principal.Id.Resource); err == nil {
+		user, err = d.client.GetUserDetails(ctx, userId)
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: unassign device: failed to get user %d: %w", userId, err)
+		}
+	} else {
+		user = &jamf.User{BaseType: jamf.BaseType{Name: principal.Id.Resource}}
+	}
+	if !isAssignedTo(username, email, user) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	err = d.setDeviceAssignee(ctx, phase, deviceId, "", "", "")
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to unassign %s %d: %w", phase, deviceId, err)
+	}
+	d.recordDeviceOwner(entitlement.Resource, "", "")
+
+	return nil, nil
+}
+
+// getDeviceAssignee reads the username and email a device is currently
+// assigned to. Jamf doesn't return a mobile device's email outside its
+// detail sections, so it's always empty for mobile devices.
+func (d *managedDeviceResourceType) getDeviceAssignee(ctx context.Context, phase string, deviceId int) (string, string, error) {
+	if phase == devicePhaseMobile {
+		device, err := d.client.GetMobileDevice(ctx, deviceId)
+		if err != nil {
+			return "", "", fmt.Errorf("jamf-connector: failed to get mobile device %d: %w", deviceId, err)
+		}
+		return device.Username, "", nil
+	}
+
+	computer, err := d.client.GetComputerInventoryDetail(ctx, deviceId)
+	if err != nil {
+		return "", "", fmt.Errorf("jamf-connector: failed to get computer %d: %w", deviceId, err)
+	}
+	if computer.UserAndLocation == nil {
+		return "", "", nil
+	}
+	return computer.UserAndLocation.Username, computer.UserAndLocation.EmailAddr(), nil
+}
+
+// setDeviceAssignee writes a device's assignee. Empty strings unassign it.
+func (d *managedDeviceResourceType) setDeviceAssignee(ctx context.Context, phase string, deviceId int, username, realName, email string) error {
+	if phase == devicePhaseMobile {
+		return d.client.UpdateMobileDeviceLocation(ctx, deviceId, jamf.MobileDeviceLocationUpdate{
+			Username:     &username,
+			RealName:     &realName,
+			EmailAddress: &email,
+		})
+	}
+	return d.client.UpdateComputerUserAndLocation(ctx, deviceId, jamf.ComputerUserAndLocationUpdate{
+		Username: &username,
+		Realname: &realName,
+		Email:    &email,
+	})
+}
+
+// isAssignedTo reports whether a device's assignee identity resolves to user,
+// by the same username and email keys resolveUser matches on.
+func isAssignedTo(username, email string, user *jamf.User) bool {
+	_, ok := resolveUser(newUserIndex([]*jamf.User{user}), username, email)
+	return ok
 }
 
 func managedDeviceBuilder(client *jamf.Client) *managedDeviceResourceType {
@@ -203,6 +364,13 @@ func (d *managedDeviceResourceType) getUserIndex(ctx context.Context) (map[strin
 		return nil, err
 	}
 
+	d.userIndex = newUserIndex(users)
+	return d.userIndex, nil
+}
+
+// newUserIndex builds the username/email -> user ResourceId lookup
+// resolveUser searches.
+func newUserIndex(users []*jamf.User) map[string]*v2.ResourceId {
 	idx := make(map[string]*v2.ResourceId, len(users))
 	for _, u := range users {
 		if u == nil {
@@ -223,9 +391,7 @@ func (d *managedDeviceResourceType) getUserIndex(ctx context.Context) (map[strin
 			}
 		}
 	}
-
-	d.userIndex = idx
-	return d.userIndex, nil
+	return idx
 }
 
 // computerResource maps a Jamf computer-inventory record onto a ManagedDevice
@@ -340,6 +506,20 @@ func deviceObjectID(phase, id string) string {
 	return fmt.Sprintf("%s:%s", phase, id)
 }
 
+// parseDeviceObjectID splits a device ID built by deviceObjectID back into
+// its source phase and the Jamf ID of the device.
+func parseDeviceObjectID(objectID string) (string, int, error) {
+	phase, id, ok := strings.Cut(objectID, ":")
+	if !ok || (phase != devicePhaseComputer && phase != devicePhaseMobile) {
+		return "", 0, fmt.Errorf("jamf-connector: invalid device id %q", objectID)
+	}
+	deviceId, err := strconv.Atoi(id)
+	if err != nil {
+		return "", 0, fmt.Errorf("jamf-connector: invalid device id %q: %w", objectID, err)
+	}
+	return phase, deviceId, nil
+}
+
 // newDevicePageToken encodes the next zero-indexed page together with the number
 // of records seen across all prior pages. Carrying the cumulative count lets
 // pagination terminate on actual progress rather than on the requested pageSize,
@@ -545,13 +725,15 @@ func resolveUser(idx map[string]*v2.ResourceId, username, email string) (*v2.Res
 
 // recordDeviceOwner caches a device's assignee identity (keyed by device
 // resource id) while listing devices, so Entitlements/Grants can emit the
-// device->user grant. No-op when the device reports no owner.
+// device->user grant. Grant and Revoke refresh it after changing the
+// assignee; a device reporting no owner has its entry cleared.
 func (d *managedDeviceResourceType) recordDeviceOwner(resource *v2.Resource, username, email string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	if username == "" && email == "" {
+		delete(d.deviceOwners, resource.GetId().GetResource())
 		return
 	}
-	d.mu.Lock()
-	defer d.mu.Unlock()
 	if d.deviceOwners == nil {
 		d.deviceOwners = make(map[string]deviceOwner)
 	}
@@ -567,6 +749,34 @@ func (d *managedDeviceResourceType) deviceAssignee(resource *v2.Resource) (strin
 	return o.username, o.email
 }
 
+// recordLocalAdmins caches the usernames of a computer's local admin accounts
+// while listing devices. No-op when the inventory lists none.
+func (d *managedDeviceResourceType) recordLocalAdmins(resource *v2.Resource, accounts []jamf.ComputerLocalUserAccount) {
+	var admins []string
+	for _, account := range accounts {
+		if account.Admin && strings.TrimSpace(account.Username) != "" {
+			admins = append(admins, account.Username)
+		}
+	}
+	if len(admins) == 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.localAdmins == nil {
+		d.localAdmins = make(map[string][]string)
+	}
+	d.localAdmins[resource.GetId().GetResource()] = admins
+}
+
+// deviceLocalAdmins returns the local admin usernames recorded for a computer
+// during List.
+func (d *managedDeviceResourceType) deviceLocalAdmins(resource *v2.Resource) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.localAdmins[resource.GetId().GetResource()]
+}
+
 // deviceGrants builds the device->user grant(s) for the assignee. A synced Jamf
 // user is granted directly; an unsynced assignee produces a grant carrying an
 // ExternalResourceMatch so the platform binds it to a directory identity.
@@ -575,8 +785,32 @@ func deviceGrants(resource *v2.Resource, username, email string, userIndex map[s
 		return nil, nil
 	}
 
+	g, err := userGrant(resource, assignedEntitlement, username, email, userIndex)
+	if err != nil {
+		return nil, err
+	}
+	return []*v2.Grant{g}, nil
+}
+
+// localAdminGrants builds a computer's "local_admin" grants, resolving each
+// local account by username the same way deviceGrants resolves an assignee.
+func localAdminGrants(resource *v2.Resource, admins []string, userIndex map[string]*v2.ResourceId) ([]*v2.Grant, error) {
+	var rv []*v2.Grant
+	for _, username := range admins {
+		g, err := userGrant(resource, localAdminEntitlement, username, "", userIndex)
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, g)
+	}
+	return rv, nil
+}
+
+// userGrant grants entitlement to the synced Jamf user matching username or
+// email, or to an ExternalResourceMatch on them when there is none.
+func userGrant(resource *v2.Resource, entitlement, username, email string, userIndex map[string]*v2.ResourceId) (*v2.Grant, error) {
 	if rid, ok := resolveUser(userIndex, username, email); ok {
-		return []*v2.Grant{grant.NewGrant(resource, assignedEntitlement, rid)}, nil
+		return grant.NewGrant(resource, entitlement, rid), nil
 	}
 
 	key, value := matchKeyEmail, strings.TrimSpace(email)
@@ -594,7 +828,7 @@ func deviceGrants(resource *v2.Resource, username, email string, userIndex map[s
 		Value:        value,
 	}.Build()
 
-	return []*v2.Grant{grant.NewGrant(resource, assignedEntitlement, principal, grant.WithAnnotation(match))}, nil
+	return grant.NewGrant(resource, entitlement, principal, grant.WithAnnotation(match)), nil
 }
 
 // parseJamfTime parses the ISO-8601 timestamps Jamf returns. Returns ok=false
@@ -0,0 +1,320 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	config "github.com/conductorone/baton-sdk/pb/c1/config/v1"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/actions"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// Names of the managedDevice resource actions.
+	lockDeviceAction       = "lock_device"
+	eraseDeviceAction      = "erase_device"
+	restartDeviceAction    = "restart_device"
+	removeMDMProfileAction = "remove_mdm_profile"
+	renewMDMProfileAction  = "renew_mdm_profile"
+
+	// Arguments and return fields of the actions.
+	deviceActionResourceArg    = "resource_id"
+	deviceActionPinArg         = "pin"
+	deviceActionMessageArg     = "message"
+	deviceActionPhoneNumberArg = "phone_number"
+	deviceActionCommandUUID    = "command_uuid"
+)
+
+// devicePinPattern is the six-digit PIN a Mac asks for once it's locked or
+// erased.
+var devicePinPattern = regexp.MustCompile(`^\d{6}$`)
+
+// deviceCommandTarget is the device an action sends its MDM command to. MDM
+// commands name devices by management ID and profile renewals by UDID, so
+// both are looked up from the inventory.
+type deviceCommandTarget struct {
+	phase        string
+	id           int
+	managementID string
+	udid         string
+}
+
+// ResourceActions registers the remote MDM commands that can be sent to a
+// device. Each action returns the command's UUID, so its status can be
+// followed in Jamf, except renew_mdm_profile, for which Jamf doesn't return
+// one.
+func (d *managedDeviceResourceType) ResourceActions(ctx context.Context, registry actions.ActionRegistry) error {
+	deviceArg := config.Field_builder{
+		Name:            deviceActionResourceArg,
+		DisplayName:     "Device",
+		Description:     "The computer or mobile device to send the command to",
+		IsRequired:      true,
+		ResourceIdField: &config.ResourceIdField{},
+	}.Build()
+	pinArg := func(required bool, description string) *config.Field {
+		return config.Field_builder{
+			Name:        deviceActionPinArg,
+			DisplayName: "PIN",
+			Description: description,
+			IsRequired:  required,
+			IsSecret:    true,
+			StringField: &config.StringField{},
+		}.Build()
+	}
+	stringArg := func(name, displayName, description string) *config.Field {
+		return config.Field_builder{
+			Name:        name,
+			DisplayName: displayName,
+			Description: description,
+			StringField: &config.StringField{},
+		}.Build()
+	}
+	commandUUID := []*config.Field{config.Field_builder{
+		Name:        deviceActionCommandUUID,
+		DisplayName: "Command UUID",
+		Description: "The UUID of the MDM command Jamf queued",
+		StringField: &config.StringField{},
+	}.Build()}
+
+	for _, action := range []struct {
+		schema  *v2.BatonActionSchema
+		handler actions.ActionHandler
+	}{
+		{
+			schema: v2.BatonActionSchema_builder{
+				Name:        lockDeviceAction,
+				DisplayName: "Lock Device",
+				Description: "Lock the device with a passcode. A Mac can only be unlocked again with the PIN.",
+				Arguments: []*config.Field{
+					deviceArg,
+					pinArg(false, "The six-digit PIN that unlocks a Mac. Required for computers; mobile devices unlock with their own passcode."),
+					stringArg(deviceActionMessageArg, "Message", "A message to show on the lock screen"),
+					stringArg(deviceActionPhoneNumberArg, "Phone Number", "A phone number to show on the lock screen"),
+				},
+				ReturnTypes: commandUUID,
+			}.Build(),
+			handler: d.lockDevice,
+		},
+		{
+			schema: v2.BatonActionSchema_builder{
+				Name:        eraseDeviceAction,
+				DisplayName: "Erase Device",
+				Description: "Erase all content and settings from the device. This can't be undone.",
+				Arguments: []*config.Field{
+					deviceArg,
+					pinArg(false, "The six-digit PIN a Mac asks for before it can be set up again"),
+				},
+				ReturnTypes: commandUUID,
+			}.Build(),
+			handler: d.eraseDevice,
+		},
+		{
+			schema: v2.BatonActionSchema_builder{
+				Name:        restartDeviceAction,
+				DisplayName: "Restart Device",
+				Description: "Restart the device",
+				Arguments:   []*config.Field{deviceArg},
+				ReturnTypes: commandUUID,
+			}.Build(),
+			handler: d.restartDevice,
+		},
+		{
+			schema: v2.BatonActionSchema_builder{
+				Name:        removeMDMProfileAction,
+				DisplayName: "Remove MDM Profile",
+				Description: "Remove the MDM profile from a computer, which stops Jamf managing it. Only computers are supported.",
+				Arguments:   []*config.Field{deviceArg},
+				ReturnTypes: commandUUID,
+			}.Build(),
+			handler: d.removeMDMProfile,
+		},
+		{
+			schema: v2.BatonActionSchema_builder{
+				Name:        renewMDMProfileAction,
+				DisplayName: "Renew MDM Profile",
+				Description: "Renew the device's MDM profile, e.g. ahead of the expiry of its certificate",
+				Arguments:   []*config.Field{deviceArg},
+			}.Build(),
+			handler: d.renewMDMProfile,
+		},
+	} {
+		if err := registry.Register(ctx, action.schema, action.handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *managedDeviceResourceType) lockDevice(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	target, err := d.getCommandTarget(ctx, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	pin, _ := actions.GetStringArg(args, deviceActionPinArg)
+	if pin == "" && target.phase == devicePhaseComputer {
+		return nil, nil, fmt.Errorf("jamf-connector: a six-digit pin is required to lock a computer")
+	}
+	if err := validateDevicePin(pin); err != nil {
+		return nil, nil, err
+	}
+	message, _ := actions.GetStringArg(args, deviceActionMessageArg)
+	phoneNumber, _ := actions.GetStringArg(args, deviceActionPhoneNumberArg)
+
+	return d.sendMDMCommand(ctx, target, jamf.MDMCommandData{
+		CommandType: jamf.MDMCommandDeviceLock,
+		Pin:         pin,
+		Message:     message,
+		PhoneNumber: phoneNumber,
+	})
+}
+
+func (d *managedDeviceResourceType) eraseDevice(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	target, err := d.getCommandTarget(ctx, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	pin, _ := actions.GetStringArg(args, deviceActionPinArg)
+	if err := validateDevicePin(pin); err != nil {
+		return nil, nil, err
+	}
+
+	return d.sendMDMCommand(ctx, target, jamf.MDMCommandData{CommandType: jamf.MDMCommandEraseDevice, Pin: pin})
+}
+
+func (d *managedDeviceResourceType) restartDevice(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	target, err := d.getCommandTarget(ctx, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return d.sendMDMCommand(ctx, target, jamf.MDMCommandData{CommandType: jamf.MDMCommandRestartDevice})
+}
+
+// removeMDMProfile unmanages a computer. Jamf only offers the command for
+// computers; a mobile device is unmanaged by erasing it or from the device.
+func (d *managedDeviceResourceType) removeMDMProfile(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	phase, id, err := deviceActionTarget(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if phase != devicePhaseComputer {
+		return nil, nil, fmt.Errorf("jamf-connector: the MDM profile can only be removed from computers, got %s device %d", phase, id)
+	}
+
+	commandUUID, err := d.client.RemoveComputerMDMProfile(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to remove the MDM profile of computer %d: %w", id, err)
+	}
+
+	return actions.NewReturnValues(true, actions.NewStringReturnField(deviceActionCommandUUID, commandUUID)), nil, nil
+}
+
+// renewMDMProfile renews a device's MDM profile. Renewal names the device by
+// UDID alone, so unlike the MDM commands it doesn't need a management ID.
+func (d *managedDeviceResourceType) renewMDMProfile(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	phase, id, err := deviceActionTarget(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := d.lookupDeviceCommandTarget(ctx, phase, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target.udid == "" {
+		return nil, nil, fmt.Errorf("jamf-connector: %s %d has no UDID; it may not be enrolled in MDM", phase, id)
+	}
+
+	err = d.client.RenewMDMProfile(ctx, target.udid)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to renew the MDM profile of %s %d: %w", target.phase, target.id, err)
+	}
+
+	return actions.NewReturnValues(true), nil, nil
+}
+
+// sendMDMCommand sends command to target and returns the command's UUID.
+func (d *managedDeviceResourceType) sendMDMCommand(
+	ctx context.Context,
+	target *deviceCommandTarget,
+	command jamf.MDMCommandData,
+) (*structpb.Struct, annotations.Annotations, error) {
+	commandUUID, err := d.client.SendMDMCommand(ctx, target.managementID, command)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to send %s to %s %d: %w", command.CommandType, target.phase, target.id, err)
+	}
+
+	return actions.NewReturnValues(true, actions.NewStringReturnField(deviceActionCommandUUID, commandUUID)), nil, nil
+}
+
+// getCommandTarget looks up the management ID and UDID of the device an
+// action names.
+func (d *managedDeviceResourceType) getCommandTarget(ctx context.Context, args *structpb.Struct) (*deviceCommandTarget, error) {
+	phase, id, err := deviceActionTarget(args)
+	if err != nil {
+		return nil, err
+	}
+	return d.getDeviceCommandTarget(ctx, phase, id)
+}
+
+// getDeviceCommandTarget looks up the management ID and UDID of a computer or
+// mobile device, refusing one with no management ID to send commands to.
+func (d *managedDeviceResourceType) getDeviceCommandTarget(ctx context.Context, phase string, id int) (*deviceCommandTarget, error) {
+	target, err := d.lookupDeviceCommandTarget(ctx, phase, id)
+	if err != nil {
+		return nil, err
+	}
+	if target.managementID == "" {
+		return nil, fmt.Errorf("jamf-connector: %s %d has no management ID; it may not be enrolled in MDM", phase, id)
+	}
+	return target, nil
+}
+
+// lookupDeviceCommandTarget looks up the management ID and UDID of a computer
+// or mobile device, either of which may be empty.
+func (d *managedDeviceResourceType) lookupDeviceCommandTarget(ctx context.Context, phase string, id int) (*deviceCommandTarget, error) {
+	ctx = jamf.WithoutCache(ctx)
+	target := &deviceCommandTarget{phase: phase, id: id}
+	if phase == devicePhaseMobile {
+		device, err := d.client.GetMobileDevice(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to get mobile device %d: %w", id, err)
+		}
+		target.managementID, target.udid = device.ManagementID, device.UDID
+	} else {
+		computer, err := d.client.GetComputerInventoryDetail(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to get computer %d: %w", id, err)
+		}
+		target.udid = computer.UDID
+		if computer.General != nil {
+			target.managementID = computer.General.ManagementID
+		}
+	}
+	return target, nil
+}
+
+// deviceActionTarget parses the device an action names out of its
+// resource_id argument.
+func deviceActionTarget(args *structpb.Struct) (string, int, error) {
+	resourceId, err := actions.RequireResourceIDArg(args, deviceActionResourceArg)
+	if err != nil {
+		return "", 0, fmt.Errorf("jamf-connector: %w", err)
+	}
+	if resourceId.GetResourceType() != resourceTypeManagedDevice.Id {
+		return "", 0, fmt.Errorf("jamf-connector: device actions need a %s resource, got %s", resourceTypeManagedDevice.Id, resourceId.GetResourceType())
+	}
+	return parseDeviceObjectID(resourceId.GetResource())
+}
+
+// validateDevicePin checks an optional PIN is six digits, as Jamf requires.
+func validateDevicePin(pin string) error {
+	if pin != "" && !devicePinPattern.MatchString(pin) {
+		return fmt.Errorf("jamf-connector: the pin must be six digits")
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestDeviceActionTarget(t *testing.T) {
+	args := func(resourceType, resource string) *structpb.Struct {
+		return resourceIDArgs(t, deviceActionResourceArg, resourceType, resource)
+	}
+
+	phase, id, err := deviceActionTarget(args(resourceTypeManagedDevice.Id, "mobile:2"))
+	if err != nil || phase != devicePhaseMobile || id != 2 {
+		t.Fatalf("expected mobile device 2, got %s %d (err %v)", phase, id, err)
+	}
+	if _, _, err := deviceActionTarget(args(resourceTypeUser.Id, "2")); err == nil {
+		t.Error("expected a user resource to be refused")
+	}
+	if _, _, err := deviceActionTarget(&structpb.Struct{}); err == nil {
+		t.Error("expected a missing resource_id to be refused")
+	}
+}
+
+func TestValidateDevicePin(t *testing.T) {
+	for pin, valid := range map[string]bool{"": true, "123456": true, "12345": false, "1234567": false, "12345a": false} {
+		if err := validateDevicePin(pin); (err == nil) != valid {
+			t.Errorf("validateDevicePin(%q) = %v, want valid %v", pin, err, valid)
+		}
+	}
+}
+
+// TestRenewMDMProfile_NeedsOnlyTheUDID checks that a profile renewal goes
+// through for a device with a UDID but no management ID, is refused for one
+// with no UDID, and that MDM commands still need the management ID.
+func TestRenewMDMProfile_NeedsOnlyTheUDID(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		renewed  []string
+		commands int
+	)
+	computers := map[string]jamf.ComputerInventory{
+		"1": {UDID: "udid-1", General: &jamf.ComputerGeneral{}},
+		"2": {General: &jamf.ComputerGeneral{ManagementID: "management-2"}},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/computers-inventory-detail/{id}", func(w http.ResponseWriter, r *http.Request) {
+		computer, ok := computers[r.PathValue("id")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(computer)
+	})
+	mux.HandleFunc("/api/v1/mdm/renew-profile", func(w http.ResponseWriter, r *http.Request) {
+		var body jamf.RenewMDMProfileBody
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		renewed = append(renewed, body.UDIDs...)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jamf.RenewMDMProfileResponse{})
+	})
+	mux.HandleFunc("/api/v2/mdm/commands", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		commands++
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	})
+	d := managedDeviceBuilder(newTestClient(t, mux))
+	ctx := context.Background()
+	args := func(id string) *structpb.Struct {
+		return resourceIDArgs(t, deviceActionResourceArg, resourceTypeManagedDevice.Id, devicePhaseComputer+":"+id)
+	}
+
+	if _, _, err := d.renewMDMProfile(ctx, args("1")); err != nil {
+		t.Fatalf("expected a device without a management ID to renew by UDID, got %v", err)
+	}
+	if !slices.Equal(renewed, []string{"udid-1"}) {
+		t.Fatalf("expected udid-1 to be renewed, got %v", renewed)
+	}
+
+	if _, _, err := d.renewMDMProfile(ctx, args("2")); err == nil || !strings.Contains(err.Error(), "UDID") {
+		t.Errorf("expected a device without a UDID to be refused, got %v", err)
+	}
+	if _, _, err := d.restartDevice(ctx, args("1")); err == nil || !strings.Contains(err.Error(), "management ID") {
+		t.Errorf("expected a command to a device without a management ID to be refused, got %v", err)
+	}
+	if len(renewed) != 1 || commands != 0 {
+		t.Errorf("expected nothing more to be sent, got renewals %v and %d commands", renewed, commands)
+	}
+}
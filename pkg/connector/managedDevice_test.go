@@ -235,14 +235,15 @@ func TestComputerResource_NoAssignee(t *testing.T) {
 		t.Fatalf("computerResource: %v", err)
 	}
 
-	// No assignee -> no entitlement and no grant.
+	// No assignee -> the assigned entitlement, so the device can be granted,
+	// but no grant.
 	d := &managedDeviceResourceType{}
 	ents, _, err := d.Entitlements(context.Background(), r, rs.SyncOpAttrs{})
 	if err != nil {
 		t.Fatalf("Entitlements: %v", err)
 	}
-	if len(ents) != 0 {
-		t.Errorf("want 0 entitlements for unassigned device, got %d", len(ents))
+	if len(ents) != 1 || ents[0].GetSlug() != assignedEntitlement {
+		t.Errorf("want only the %s entitlement for unassigned device, got %v", assignedEntitlement, ents)
 	}
 	grants, err := deviceGrants(r, "", "", testUserIndex())
 	if err != nil {
@@ -253,6 +254,56 @@ func TestComputerResource_NoAssignee(t *testing.T) {
 	}
 }
 
+func TestComputerResource_LocalAdmins(t *testing.T) {
+	c := &jamf.ComputerInventory{
+		ID:      "6",
+		General: &jamf.ComputerGeneral{Name: "Shared Mac"},
+		LocalUserAccounts: []jamf.ComputerLocalUserAccount{
+			{Username: "jappleseed", Admin: true},
+			{Username: "guest"},
+			{Username: "itadmin", Admin: true},
+		},
+	}
+
+	r, err := computerResource(c, nil)
+	if err != nil {
+		t.Fatalf("computerResource: %v", err)
+	}
+
+	// Local admins but no assignee -> assigned and local_admin entitlements.
+	d := &managedDeviceResourceType{}
+	d.recordLocalAdmins(r, c.LocalUserAccounts)
+	ents, _, err := d.Entitlements(context.Background(), r, rs.SyncOpAttrs{})
+	if err != nil {
+		t.Fatalf("Entitlements: %v", err)
+	}
+	if len(ents) != 2 || ents[0].GetSlug() != assignedEntitlement || ents[1].GetSlug() != localAdminEntitlement {
+		t.Fatalf("want the %s and %s entitlements, got %v", assignedEntitlement, localAdminEntitlement, ents)
+	}
+
+	// Non-admin accounts are skipped; a synced user is granted directly and an
+	// unsynced account gets an external match on its username.
+	grants, err := localAdminGrants(r, d.deviceLocalAdmins(r), testUserIndex())
+	if err != nil {
+		t.Fatalf("localAdminGrants: %v", err)
+	}
+	if len(grants) != 2 {
+		t.Fatalf("want 2 grants, got %d", len(grants))
+	}
+	if got, want := grants[0].GetPrincipal().GetId().GetResource(), "42"; got != want {
+		t.Errorf("grant principal = %q, want %q", got, want)
+	}
+	match := &v2.ExternalResourceMatch{}
+	grantAnnos := annotations.Annotations(grants[1].GetAnnotations())
+	ok, err := grantAnnos.Pick(match)
+	if err != nil {
+		t.Fatalf("pick ExternalResourceMatch: %v", err)
+	}
+	if !ok || match.GetKey() != "username" || match.GetValue() != "itadmin" {
+		t.Errorf("unresolved local admin should match username itadmin, got %v", match)
+	}
+}
+
 func TestMobileDeviceResource_Mapping(t *testing.T) {
 	m := &jamf.MobileDevice{
 		ID:              "3",
@@ -367,3 +418,27 @@ func TestOSTypeFromName(t *testing.T) {
 		}
 	}
 }
+
+func TestIsAssignedTo(t *testing.T) {
+	user := &jamf.User{BaseType: jamf.BaseType{ID: 3, Name: "carol.smith"}, Email: "carol.smith@example.com"}
+	tests := []struct {
+		name            string
+		username, email string
+		user            *jamf.User
+		want            bool
+	}{
+		{name: "by username", username: "Carol.Smith", user: user, want: true},
+		{name: "by email", email: "carol.smith@example.com", user: user, want: true},
+		{name: "someone else", username: "jane.doe", email: "jane.doe@example.com", user: user},
+		{name: "unassigned", user: user},
+		// Revoking an external-match grant compares against its principal ID.
+		{name: "unsynced assignee", username: "field.tech", user: &jamf.User{BaseType: jamf.BaseType{Name: "field.tech"}}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAssignedTo(tt.username, tt.email, tt.user); got != tt.want {
+				t.Errorf("isAssignedTo(%q, %q) = %v, want %v", tt.username, tt.email, got, tt.want)
+			}
+		})
+	}
+}
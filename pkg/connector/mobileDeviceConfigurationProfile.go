@@ -0,0 +1,113 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type mobileDeviceConfigurationProfileResourceType struct {
+	resourceType *v2.ResourceType
+	client       *jamf.Client
+}
+
+func (o *mobileDeviceConfigurationProfileResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Jamf mobile device configuration
+// profile.
+func mobileDeviceConfigurationProfileResource(configurationProfile *jamf.MobileDeviceConfigurationProfile, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := configurationProfileProfile(&configurationProfile.General)
+	profile["all_mobile_devices"] = configurationProfile.Scope.AllMobileDevices
+
+	ret, err := rs.NewResource(
+		configurationProfile.General.Name,
+		resourceTypeMobileDeviceConfigurationProfile,
+		configurationProfile.General.ID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithResourceProfile(profile),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *mobileDeviceConfigurationProfileResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	bag, offset, pageSize, err := parseOffsetPageToken(attrs.PageToken, o.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configurationProfiles, total, err := o.client.GetMobileDeviceConfigurationProfilesPage(ctx, offset, pageSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list mobile device configuration profiles: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, configurationProfile := range configurationProfiles {
+		pr, err := mobileDeviceConfigurationProfileResource(configurationProfile, parentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, pr)
+	}
+
+	nextToken, err := nextOffsetPageToken(bag, offset+pageSize, total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rv, &rs.SyncOpResults{NextPageToken: nextToken}, nil
+}
+
+func (o *mobileDeviceConfigurationProfileResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeManagedDevice, resourceTypeMobileDeviceGroup, resourceTypeBuilding, resourceTypeDepartment),
+		ent.WithDescription(fmt.Sprintf("In scope of the %s mobile device configuration profile in Jamf", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s Configuration Profile %s", resource.DisplayName, scopedEntitlement)),
+	}
+
+	en := ent.NewAssignmentEntitlement(resource, scopedEntitlement, assignmentOptions...)
+	rv = append(rv, en)
+
+	return rv, nil, nil
+}
+
+// Grants emits the profile's scope, with exclusions applied (see
+// scopeGrants).
+func (o *mobileDeviceConfigurationProfileResourceType) Grants(ctx context.Context, resource *v2.Resource, attrs rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	profileId, err := strconv.Atoi(resource.Id.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configurationProfile, err := o.client.GetMobileDeviceConfigurationProfileDetails(ctx, profileId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rv, err := scopeGrants(ctx, o.client, resource, scopedEntitlement, mobileDeviceScope(&configurationProfile.Scope))
+	if err != nil {
+		return nil, nil, err
+	}
+	return rv, nil, nil
+}
+
+func mobileDeviceConfigurationProfileBuilder(client *jamf.Client) *mobileDeviceConfigurationProfileResourceType {
+	return &mobileDeviceConfigurationProfileResourceType{
+		resourceType: resourceTypeMobileDeviceConfigurationProfile,
+		client:       client,
+	}
+}
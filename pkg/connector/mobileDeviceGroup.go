@@ -0,0 +1,126 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type mobileDeviceGroupResourceType struct {
+	resourceType *v2.ResourceType
+	client       *jamf.Client
+}
+
+func (g *mobileDeviceGroupResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return g.resourceType
+}
+
+// Create a new connector resource for a Jamf mobile device group.
+func mobileDeviceGroupResource(group *jamf.MobileDeviceGroup, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"mobile_device_group_id":   group.ID,
+		"mobile_device_group_name": group.Name,
+		"is_smart":                 group.IsSmart,
+		"group_type":               deviceGroupType(group.IsSmart),
+	}
+	if group.Site.Name != "" {
+		profile["site_id"] = group.Site.ID
+		profile["site_name"] = group.Site.Name
+	}
+
+	ret, err := rs.NewGroupResource(
+		group.Name,
+		resourceTypeMobileDeviceGroup,
+		group.ID,
+		[]rs.GroupTraitOption{rs.WithGroupProfile(profile)},
+		rs.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (g *mobileDeviceGroupResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	bag, offset, pageSize, err := parseOffsetPageToken(attrs.PageToken, g.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mobileDeviceGroups, total, err := g.client.GetMobileDeviceGroupsPage(ctx, offset, pageSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list mobile device groups: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, mobileDeviceGroup := range mobileDeviceGroups {
+		cr, err := mobileDeviceGroupResource(mobileDeviceGroup, parentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, cr)
+	}
+
+	nextToken, err := nextOffsetPageToken(bag, offset+pageSize, total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rv, &rs.SyncOpResults{NextPageToken: nextToken}, nil
+}
+
+func (g *mobileDeviceGroupResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeManagedDevice),
+		ent.WithDescription(fmt.Sprintf("Member of %s Mobile Device Group in Jamf", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s Mobile Device Group %s", resource.DisplayName, memberEntitlement)),
+	}
+
+	en := ent.NewAssignmentEntitlement(resource, memberEntitlement, assignmentOptions...)
+	rv = append(rv, en)
+
+	return rv, nil, nil
+}
+
+// Grants emits a member grant to the managedDevice resource of every mobile
+// device in the group, smart or static.
+func (g *mobileDeviceGroupResourceType) Grants(ctx context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	var rv []*v2.Grant
+
+	mobileDeviceGroupId, err := strconv.Atoi(resource.Id.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	group, err := g.client.GetMobileDeviceGroupDetails(ctx, mobileDeviceGroupId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, mobileDevice := range group.MobileDevices {
+		deviceId, err := rs.NewResourceID(resourceTypeManagedDevice, deviceObjectID(devicePhaseMobile, strconv.Itoa(mobileDevice.ID)))
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, grant.NewGrant(resource, memberEntitlement, deviceId))
+	}
+
+	return rv, nil, nil
+}
+
+func mobileDeviceGroupBuilder(client *jamf.Client) *mobileDeviceGroupResourceType {
+	return &mobileDeviceGroupResourceType{
+		resourceType: resourceTypeMobileDeviceGroup,
+		client:       client,
+	}
+}
@@ -0,0 +1,383 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	config "github.com/conductorone/baton-sdk/pb/c1/config/v1"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/actions"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	offboardUserAction = "offboard_user"
+
+	// Arguments of offboard_user. The lock PIN and message reuse the
+	// lock_device arguments.
+	offboardLoginArg         = "login"
+	offboardDeleteUserArg    = "delete_user"
+	offboardDeleteAccountArg = "delete_account"
+	offboardLockDevicesArg   = "lock_devices"
+
+	// offboardStepsField is the return field carrying the report.
+	offboardStepsField = "steps"
+
+	// Outcomes of an offboarding step.
+	offboardStepDone    = "done"
+	offboardStepSkipped = "skipped"
+	offboardStepFailed  = "failed"
+)
+
+// offboardStep is one entry of the offboard_user report.
+type offboardStep struct {
+	step   string
+	target string
+	status string
+	detail string
+}
+
+// offboardReport collects the steps of an offboarding in the order they ran.
+// A failed step doesn't stop the ones after it, so the report shows
+// everything that was and wasn't done.
+type offboardReport struct {
+	steps []offboardStep
+}
+
+func (r *offboardReport) done(step, target, detail string) {
+	r.steps = append(r.steps, offboardStep{step: step, target: target, status: offboardStepDone, detail: detail})
+}
+
+func (r *offboardReport) skipped(step, target, detail string) {
+	r.steps = append(r.steps, offboardStep{step: step, target: target, status: offboardStepSkipped, detail: detail})
+}
+
+func (r *offboardReport) failed(step, target string, err error) {
+	r.steps = append(r.steps, offboardStep{step: step, target: target, status: offboardStepFailed, detail: err.Error()})
+}
+
+// succeeded reports whether no step failed.
+func (r *offboardReport) succeeded() bool {
+	for _, step := range r.steps {
+		if step.status == offboardStepFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// lines renders each step as "<step> <target>: <status>", followed by
+// " (<detail>)" when there are details.
+func (r *offboardReport) lines() []string {
+	rv := make([]string, 0, len(r.steps))
+	for _, step := range r.steps {
+		line := fmt.Sprintf("%s %s: %s", step.step, step.target, step.status)
+		if step.detail != "" {
+			line += " (" + step.detail + ")"
+		}
+		rv = append(rv, line)
+	}
+	return rv
+}
+
+// GlobalActions registers offboard_user, which runs every step of taking a
+// leaver's access away in Jamf.
+func (j *Jamf) GlobalActions(ctx context.Context, registry actions.ActionRegistry) error {
+	boolArg := func(name, displayName, description string) *config.Field {
+		return config.Field_builder{
+			Name:        name,
+			DisplayName: displayName,
+			Description: description,
+			BoolField:   &config.BoolField{},
+		}.Build()
+	}
+	stringArg := func(name, displayName, description string, required, secret bool) *config.Field {
+		return config.Field_builder{
+			Name:        name,
+			DisplayName: displayName,
+			Description: description,
+			IsRequired:  required,
+			IsSecret:    secret,
+			StringField: &config.StringField{},
+		}.Build()
+	}
+
+	schema := v2.BatonActionSchema_builder{
+		Name:        offboardUserAction,
+		DisplayName: "Offboard User",
+		Description: "Remove a leaver's access across Jamf: take their user out of static user groups, clear their device assignments " +
+			"(optionally locking the devices first), take their user account out of admin groups and disable it, " +
+			"and optionally delete the user and the user account. Returns a report of every step.",
+		Arguments: []*config.Field{
+			stringArg(offboardLoginArg, "Login", "The username or email address of the user and user account to offboard", true, false),
+			boolArg(offboardDeleteUserArg, "Delete User", "Delete the user once it's offboarded. Jamf users can't be disabled."),
+			boolArg(offboardDeleteAccountArg, "Delete User Account", "Delete the user account instead of disabling it"),
+			boolArg(offboardLockDevicesArg, "Lock Devices", "Lock the devices assigned to the user before unassigning them"),
+			stringArg(deviceActionPinArg, "Lock PIN", "The six-digit PIN that unlocks the locked computers. Required to lock computers.", false, true),
+			stringArg(deviceActionMessageArg, "Lock Message", "A message to show on the lock screen of the locked devices", false, false),
+		},
+		ReturnTypes: []*config.Field{
+			config.Field_builder{
+				Name:             offboardStepsField,
+				DisplayName:      "Steps",
+				Description:      "One line per step run: the step, its target, its status (done, skipped or failed) and any details",
+				StringSliceField: &config.StringSliceField{},
+			}.Build(),
+		},
+	}.Build()
+
+	return registry.Register(ctx, schema, j.offboardUser)
+}
+
+func (j *Jamf) offboardUser(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	login, err := actions.RequireStringArg(args, offboardLoginArg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: %w", err)
+	}
+	login = strings.TrimSpace(login)
+	deleteUser, _ := actions.GetBoolArg(args, offboardDeleteUserArg)
+	deleteAccount, _ := actions.GetBoolArg(args, offboardDeleteAccountArg)
+	lockDevices, _ := actions.GetBoolArg(args, offboardLockDevicesArg)
+	pin, _ := actions.GetStringArg(args, deviceActionPinArg)
+	message, _ := actions.GetStringArg(args, deviceActionMessageArg)
+	if err := validateDevicePin(pin); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := j.findOffboardUser(ctx, login)
+	if err != nil {
+		return nil, nil, err
+	}
+	identity := &jamf.User{BaseType: jamf.BaseType{Name: login}}
+	if user != nil {
+		identity = user
+	}
+	account, err := j.findOffboardAccount(ctx, login, identity)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil && account == nil {
+		return nil, nil, fmt.Errorf("jamf-connector: no user or user account matches %q", login)
+	}
+
+	report := &offboardReport{}
+	if user != nil {
+		j.offboardUserGroups(ctx, report, user)
+	} else {
+		report.skipped("find_user", login, "no Jamf user matches")
+	}
+	// Devices can be assigned to a username that has no Jamf user.
+	j.offboardDevices(ctx, report, identity, lockDevices, pin, message)
+	if user != nil {
+		target := userTarget(user)
+		if !deleteUser {
+			report.skipped("delete_user", target, "not requested")
+		} else if err := j.client.DeleteUser(ctx, user.ID); err != nil && !jamf.IsNotFoundError(err) {
+			report.failed("delete_user", target, err)
+		} else {
+			report.done("delete_user", target, "")
+		}
+	}
+
+	if account != nil {
+		// Keep the account's audit history when the connector is
+		// configured to disable accounts rather than delete them.
+		j.offboardAccount(ctx, report, account, deleteAccount && !j.disableUserAccountsOnDelete)
+	} else {
+		report.skipped("find_user_account", login, "no Jamf user account matches")
+	}
+
+	return actions.NewReturnValues(report.succeeded(), actions.NewStringListReturnField(offboardStepsField, report.lines())), nil, nil
+}
+
+// findOffboardUser finds the Jamf user whose username is login, or whose
+// email is, or nil if there's none. Both are direct lookups, so offboarding
+// never lists the whole tenant to find one user.
+func (j *Jamf) findOffboardUser(ctx context.Context, login string) (*jamf.User, error) {
+	user, err := j.client.GetUserByName(ctx, login)
+	if err == nil {
+		return user, nil
+	}
+	if !jamf.IsNotFoundError(err) {
+		return nil, fmt.Errorf("jamf-connector: offboard user: failed to get user %q: %w", login, err)
+	}
+	if !strings.Contains(login, "@") {
+		return nil, nil
+	}
+
+	users, err := j.client.GetUsersByEmail(ctx, login)
+	if err != nil {
+		if jamf.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("jamf-connector: offboard user: failed to get users with email %q: %w", login, err)
+	}
+	switch len(users) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &users[0], nil
+	default:
+		return nil, fmt.Errorf("jamf-connector: offboard user: %d users have email %q; offboard one by username instead", len(users), login)
+	}
+}
+
+// findOffboardAccount finds the Jamf user account whose username is login, or
+// the username of the user being offboarded, or nil if there's none.
+func (j *Jamf) findOffboardAccount(ctx context.Context, login string, user *jamf.User) (*jamf.UserAccount, error) {
+	names := []string{login}
+	if user.Name != "" && user.Name != login {
+		names = append(names, user.Name)
+	}
+	for _, name := range names {
+		account, err := j.client.GetUserAccountByName(ctx, name)
+		if err == nil {
+			return account, nil
+		}
+		if !jamf.IsNotFoundError(err) {
+			return nil, fmt.Errorf("jamf-connector: offboard user: failed to get user account %q: %w", name, err)
+		}
+	}
+	return nil, nil
+}
+
+// offboardUserGroups takes the user out of every static user group. Smart
+// groups are reported but left alone, since Jamf computes their membership.
+func (j *Jamf) offboardUserGroups(ctx context.Context, report *offboardReport, user *jamf.User) {
+	userGroups, err := j.client.GetUserGroups(ctx)
+	if err != nil {
+		report.failed("remove_from_user_groups", userTarget(user), err)
+		return
+	}
+
+	found := false
+	for _, group := range userGroups {
+		if !slices.ContainsFunc(group.Users, func(member jamf.User) bool { return member.ID == user.ID }) {
+			continue
+		}
+		target := fmt.Sprintf("user group %s (%d)", group.Name, group.ID)
+		if group.IsSmart {
+			report.skipped("remove_from_user_group", target, "smart group; its membership is computed by Jamf")
+			continue
+		}
+		found = true
+		if err := j.client.RemoveUserGroupMember(ctx, group.ID, user.ID); err != nil {
+			report.failed("remove_from_user_group", target, err)
+			continue
+		}
+		report.done("remove_from_user_group", target, "")
+	}
+	if !found {
+		report.skipped("remove_from_user_groups", userTarget(user), "not in any static user group")
+	}
+}
+
+// offboardDevices clears the assignment of every computer and mobile device
+// assigned to user, locking each one first when lock is set. A device that
+// fails to lock stays assigned.
+func (j *Jamf) offboardDevices(ctx context.Context, report *offboardReport, user *jamf.User, lock bool, pin, message string) {
+	type assignedDevice struct {
+		phase string
+		id    int
+	}
+	var assigned []assignedDevice
+	for _, phase := range []string{devicePhaseComputer, devicePhaseMobile} {
+		locations, err := deviceLocations(ctx, j.client, phase)
+		if err != nil {
+			report.failed("find_devices", phase, err)
+			continue
+		}
+		for _, location := range locations {
+			if isAssignedTo(location.username, location.email, user) {
+				assigned = append(assigned, assignedDevice{phase: phase, id: location.id})
+			}
+		}
+	}
+	if len(assigned) == 0 {
+		report.skipped("unassign_devices", user.Name, "no devices are assigned")
+		return
+	}
+
+	devices := managedDeviceBuilder(j.client)
+	for _, device := range assigned {
+		phase, id := device.phase, device.id
+		target := fmt.Sprintf("%s %d", phase, id)
+
+		if lock {
+			commandUUID, err := lockAssignedDevice(ctx, devices, phase, id, pin, message)
+			if err != nil {
+				// Leave an unlocked device with its assignee, so it isn't
+				// lost track of.
+				report.failed("lock_device", target, err)
+				report.skipped("unassign_device", target, "the lock failed")
+				continue
+			}
+			report.done("lock_device", target, "command "+commandUUID)
+		}
+
+		if err := devices.setDeviceAssignee(ctx, phase, id, "", "", ""); err != nil {
+			report.failed("unassign_device", target, err)
+			continue
+		}
+		report.done("unassign_device", target, "")
+	}
+}
+
+// lockAssignedDevice sends a device lock command and returns its UUID.
+func lockAssignedDevice(ctx context.Context, devices *managedDeviceResourceType, phase string, id int, pin, message string) (string, error) {
+	if pin == "" && phase == devicePhaseComputer {
+		return "", fmt.Errorf("a six-digit pin is required to lock a computer")
+	}
+	target, err := devices.getDeviceCommandTarget(ctx, phase, id)
+	if err != nil {
+		return "", err
+	}
+	return devices.client.SendMDMCommand(ctx, target.managementID, jamf.MDMCommandData{
+		CommandType: jamf.MDMCommandDeviceLock,
+		Pin:         pin,
+		Message:     message,
+	})
+}
+
+// offboardAccount takes the user account out of its admin groups, then
+// disables or deletes it.
+func (j *Jamf) offboardAccount(ctx context.Context, report *offboardReport, account *jamf.UserAccount, deleteAccount bool) {
+	target := fmt.Sprintf("user account %s (%d)", account.Name, account.ID)
+	if len(account.Groups) == 0 {
+		report.skipped("remove_from_admin_groups", target, "not in any admin group")
+	} else if err := j.client.UpdateUserAccount(ctx, account.ID, jamf.UserAccountUpdateBody{Groups: jamf.NewAccountGroups(nil)}); err != nil {
+		report.failed("remove_from_admin_groups", target, err)
+	} else {
+		names := make([]string, 0, len(account.Groups))
+		for _, group := range account.Groups {
+			names = append(names, group.Name)
+		}
+		report.done("remove_from_admin_groups", target, strings.Join(names, ", "))
+	}
+
+	switch {
+	case deleteAccount:
+		if err := j.client.DeleteUserAccount(ctx, account.ID); err != nil && !jamf.IsNotFoundError(err) {
+			report.failed("delete_user_account", target, err)
+		} else {
+			report.done("delete_user_account", target, "")
+		}
+	case account.Enabled == disabledValue:
+		report.skipped("disable_user_account", target, "already disabled")
+	default:
+		if err := j.client.UpdateUserAccount(ctx, account.ID, jamf.UserAccountUpdateBody{Enabled: disabledValue}); err != nil {
+			report.failed("disable_user_account", target, err)
+		} else {
+			report.done("disable_user_account", target, "")
+		}
+	}
+}
+
+func userTarget(user *jamf.User) string {
+	return fmt.Sprintf("user %s (%d)", user.Name, user.ID)
+}
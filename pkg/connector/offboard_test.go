@@ -0,0 +1,239 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestOffboardReport(t *testing.T) {
+	report := &offboardReport{}
+	report.done("remove_from_user_group", "user group eng (301)", "")
+	report.skipped("delete_user", "user john (1)", "not requested")
+	if !report.succeeded() {
+		t.Error("expected a report without failures to succeed")
+	}
+
+	report.failed("unassign_device", "computer 1", errors.New("boom"))
+	if report.succeeded() {
+		t.Error("expected a report with a failure not to succeed")
+	}
+
+	want := []string{
+		"remove_from_user_group user group eng (301): done",
+		"delete_user user john (1): skipped (not requested)",
+		"unassign_device computer 1: failed (boom)",
+	}
+	if got := report.lines(); !slices.Equal(got, want) {
+		t.Errorf("lines() = %q, want %q", got, want)
+	}
+}
+
+// fakeOffboardJamf serves the endpoints offboard_user calls for a leaver
+// with one user, one user account in one admin group, and two assigned
+// computers, of which only the first has a management ID to lock it by. It
+// records every request and the computers it unassigns.
+type fakeOffboardJamf struct {
+	mu sync.Mutex
+	// failAccountGroups makes the account update clearing its admin groups
+	// fail.
+	failAccountGroups bool
+	requests          []string
+	unassigned        []string
+	commands          int
+	accountUpdates    []string
+}
+
+func (f *fakeOffboardJamf) handler() http.Handler {
+	leaver := jamf.User{BaseType: jamf.BaseType{ID: 7, Name: "leaver"}, Email: "leaver@example.com"}
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, value any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(value)
+	}
+	mux.HandleFunc("GET /JSSResource/users/name/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("name") != leaver.Name {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, jamf.UserResponse{User: leaver})
+	})
+	mux.HandleFunc("GET /JSSResource/users/email/{email}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("email") {
+		case leaver.Email:
+			writeJSON(w, jamf.UsersByEmailResponse{Users: []jamf.User{leaver}})
+		case "shared@example.com":
+			writeJSON(w, jamf.UsersByEmailResponse{Users: []jamf.User{leaver, {BaseType: jamf.BaseType{ID: 8, Name: "other"}}}})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("GET /JSSResource/accounts/username/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("name") != leaver.Name {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, jamf.UserAccountResponse{UserAccount: jamf.UserAccount{
+			BaseType: jamf.BaseType{ID: 70, Name: "leaver"},
+			Enabled:  "Enabled",
+			Groups:   []jamf.BaseType{{ID: 1, Name: "admins"}},
+		}})
+	})
+	mux.HandleFunc("PUT /JSSResource/accounts/userid/70", func(w http.ResponseWriter, r *http.Request) {
+		var body jamf.UserAccountUpdateBody
+		_ = xml.NewDecoder(r.Body).Decode(&body)
+		if body.Groups != nil && f.failAccountGroups {
+			http.Error(w, "can't update groups", http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if body.Groups != nil {
+			f.accountUpdates = append(f.accountUpdates, "groups")
+		}
+		if body.Enabled != "" {
+			f.accountUpdates = append(f.accountUpdates, body.Enabled)
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("GET /JSSResource/usergroups", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, jamf.UserGroupsResponse{})
+	})
+	mux.HandleFunc("GET /api/v1/computers-inventory", func(w http.ResponseWriter, r *http.Request) {
+		location := &jamf.ComputerUserAndLocation{Username: leaver.Name}
+		writeJSON(w, map[string]any{"totalCount": 2, "results": []jamf.ComputerInventory{
+			{ID: "1", UserAndLocation: location},
+			{ID: "2", UserAndLocation: location},
+		}})
+	})
+	mux.HandleFunc("GET /api/v2/mobile-devices/detail", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"totalCount": 0, "results": []any{}})
+	})
+	mux.HandleFunc("GET /api/v1/computers-inventory-detail/{id}", func(w http.ResponseWriter, r *http.Request) {
+		general := &jamf.ComputerGeneral{}
+		if r.PathValue("id") == "1" {
+			general.ManagementID = "management-1"
+		}
+		writeJSON(w, jamf.ComputerInventory{ID: r.PathValue("id"), General: general})
+	})
+	mux.HandleFunc("PATCH /api/v1/computers-inventory-detail/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.unassigned = append(f.unassigned, r.PathValue("id"))
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("POST /api/v2/mdm/commands", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.commands++
+		f.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode([]jamf.MDMCommandResult{{ID: "command-1"}})
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+		f.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// runOffboard runs offboard_user against f and returns its success and steps.
+func runOffboard(t *testing.T, f *fakeOffboardJamf, args map[string]any) (bool, []string) {
+	t.Helper()
+	j := &Jamf{client: newTestClient(t, f.handler())}
+	actionArgs, err := structpb.NewStruct(args)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rv, _, err := j.offboardUser(context.Background(), actionArgs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var steps []string
+	for _, step := range rv.GetFields()[offboardStepsField].GetListValue().GetValues() {
+		steps = append(steps, step.GetStringValue())
+	}
+	return rv.GetFields()["success"].GetBoolValue(), steps
+}
+
+func TestOffboardUser_LockFailureKeepsTheDeviceAssigned(t *testing.T) {
+	f := &fakeOffboardJamf{}
+	ok, steps := runOffboard(t, f, map[string]any{offboardLoginArg: "leaver", offboardLockDevicesArg: true, deviceActionPinArg: "123456"})
+	if ok {
+		t.Fatalf("expected the offboarding to fail, got %q", steps)
+	}
+
+	for _, want := range []string{
+		"lock_device computer 1: done (command command-1)",
+		"unassign_device computer 1: done",
+		"unassign_device computer 2: skipped (the lock failed)",
+		"remove_from_admin_groups user account leaver (70): done (admins)",
+		"disable_user_account user account leaver (70): done",
+	} {
+		if !slices.Contains(steps, want) {
+			t.Errorf("expected step %q, got %q", want, steps)
+		}
+	}
+	if !slices.ContainsFunc(steps, func(step string) bool { return strings.HasPrefix(step, "lock_device computer 2: failed") }) {
+		t.Errorf("expected computer 2's lock to fail, got %q", steps)
+	}
+	if !slices.Equal(f.unassigned, []string{"1"}) || f.commands != 1 {
+		t.Errorf("expected only computer 1 to be locked and unassigned, got %d commands and unassigned %v", f.commands, f.unassigned)
+	}
+}
+
+func TestOffboardUser_FailedStepDoesNotStopTheRest(t *testing.T) {
+	f := &fakeOffboardJamf{failAccountGroups: true}
+	ok, steps := runOffboard(t, f, map[string]any{offboardLoginArg: "leaver"})
+	if ok {
+		t.Fatalf("expected the offboarding to fail, got %q", steps)
+	}
+
+	if !slices.ContainsFunc(steps, func(step string) bool {
+		return strings.HasPrefix(step, "remove_from_admin_groups user account leaver (70): failed")
+	}) {
+		t.Errorf("expected the admin group removal to fail, got %q", steps)
+	}
+	if !slices.Contains(steps, "disable_user_account user account leaver (70): done") || !slices.Equal(f.accountUpdates, []string{disabledValue}) {
+		t.Errorf("expected the account to be disabled anyway, got %q and updates %v", steps, f.accountUpdates)
+	}
+	if !slices.Equal(f.unassigned, []string{"1", "2"}) {
+		t.Errorf("expected both computers to be unassigned, got %v", f.unassigned)
+	}
+}
+
+// TestOffboardUser_LooksUpByEmail checks that a leaver given by email is
+// found, with their user account, by direct lookups rather than by listing
+// every user or account.
+func TestOffboardUser_LooksUpByEmail(t *testing.T) {
+	f := &fakeOffboardJamf{}
+	ok, steps := runOffboard(t, f, map[string]any{offboardLoginArg: "leaver@example.com"})
+	if !ok {
+		t.Fatalf("expected the offboarding to succeed, got %q", steps)
+	}
+	if !slices.Contains(steps, "disable_user_account user account leaver (70): done") {
+		t.Errorf("expected the user's account to be found by their username, got %q", steps)
+	}
+	for _, listing := range []string{"GET /JSSResource/users", "GET /JSSResource/accounts"} {
+		if slices.Contains(f.requests, listing) {
+			t.Errorf("expected no %s listing, got requests %q", listing, f.requests)
+		}
+	}
+
+	j := &Jamf{client: newTestClient(t, f.handler())}
+	args, _ := structpb.NewStruct(map[string]any{offboardLoginArg: "shared@example.com"})
+	if _, _, err := j.offboardUser(context.Background(), args); err == nil {
+		t.Error("expected an email shared by several users to be refused")
+	}
+}
@@ -0,0 +1,132 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type policyResourceType struct {
+	resourceType *v2.ResourceType
+	client       *jamf.Client
+}
+
+func (p *policyResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return p.resourceType
+}
+
+// Create a new connector resource for a Jamf policy.
+func policyResource(policy *jamf.Policy, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	general := policy.General
+	profile := map[string]interface{}{
+		"policy_id":     general.ID,
+		"policy_name":   general.Name,
+		"enabled":       general.Enabled,
+		"trigger":       general.Trigger,
+		"frequency":     general.Frequency,
+		"all_computers": policy.Scope.AllComputers,
+	}
+	if general.Category.Name != "" {
+		profile["category"] = general.Category.Name
+	}
+	if general.Site.Name != "" {
+		profile["site_id"] = general.Site.ID
+		profile["site_name"] = general.Site.Name
+	}
+
+	resourceStatus := v2.Status_RESOURCE_STATUS_ENABLED
+	if !general.Enabled {
+		resourceStatus = v2.Status_RESOURCE_STATUS_DISABLED
+	}
+
+	ret, err := rs.NewResource(
+		general.Name,
+		resourceTypePolicy,
+		general.ID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithResourceProfile(profile),
+		rs.WithResourceStatus(resourceStatus, ""),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (p *policyResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	bag, offset, pageSize, err := parseOffsetPageToken(attrs.PageToken, p.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policies, total, err := p.client.GetPoliciesPage(ctx, offset, pageSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to list policies: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, policy := range policies {
+		pr, err := policyResource(policy, parentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, pr)
+	}
+
+	nextToken, err := nextOffsetPageToken(bag, offset+pageSize, total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rv, &rs.SyncOpResults{NextPageToken: nextToken}, nil
+}
+
+func (p *policyResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeManagedDevice, resourceTypeComputerGroup, resourceTypeBuilding, resourceTypeDepartment),
+		ent.WithDescription(fmt.Sprintf("In scope of the %s policy in Jamf", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s Policy %s", resource.DisplayName, scopedEntitlement)),
+	}
+
+	en := ent.NewAssignmentEntitlement(resource, scopedEntitlement, assignmentOptions...)
+	rv = append(rv, en)
+
+	return rv, nil, nil
+}
+
+// Grants emits the policy's scope, with exclusions applied (see
+// scopeGrants).
+func (p *policyResourceType) Grants(ctx context.Context, resource *v2.Resource, attrs rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	policyId, err := strconv.Atoi(resource.Id.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policy, err := p.client.GetPolicyDetails(ctx, policyId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rv, err := scopeGrants(ctx, p.client, resource, scopedEntitlement, computerScope(&policy.Scope))
+	if err != nil {
+		return nil, nil, err
+	}
+	return rv, nil, nil
+}
+
+func policyBuilder(client *jamf.Client) *policyResourceType {
+	return &policyResourceType{
+		resourceType: resourceTypePolicy,
+		client:       client,
+	}
+}
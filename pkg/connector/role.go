@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/conductorone/baton-jamf/pkg/jamf"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
 	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	"github.com/conductorone/baton-sdk/pkg/types/resource"
@@ -15,6 +18,11 @@ import (
 type roleResourceType struct {
 	resourceType *v2.ResourceType
 	client       *jamf.Client
+
+	// syncAPIRoles links individual privileges to the API roles holding them.
+	// Off unless the opt-in apiRole type is synced, so installs without the
+	// "Read API Roles" privilege keep syncing roles.
+	syncAPIRoles bool
 }
 
 func (o *roleResourceType) ResourceType(_ context.Context) *v2.ResourceType {
@@ -98,6 +106,7 @@ func matchesIndividualPrivilege(privilegeSet string, privileges *jamf.Privileges
 }
 
 func (o *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, attrs resource.SyncOpAttrs) ([]*v2.Grant, *resource.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
 	var rv []*v2.Grant
 	isCustomPrivilege := !slices.Contains(privilegeSets, resource.Id.Resource)
 	userAccounts, groups, err := o.client.GetAccounts(ctx)
@@ -140,12 +149,242 @@ func (o *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, at
 			rv = append(rv, privilegeGrant)
 		}
 	}
+
+	if isCustomPrivilege && o.syncAPIRoles {
+		apiRoleGrants, err := o.apiRoleGrants(ctx, resource)
+		if err != nil {
+			return nil, nil, err
+		}
+		rv = append(rv, apiRoleGrants...)
+	}
 	return rv, nil, nil
 }
 
-func roleBuilder(client *jamf.Client) *roleResourceType {
+// apiRoleGrants grants an individual privilege to every API role holding it.
+// The grants expand through each API role's member entitlement, so the API
+// integrations authorized with the role show up as holding the privilege too.
+func (o *roleResourceType) apiRoleGrants(ctx context.Context, privilege *v2.Resource) ([]*v2.Grant, error) {
+	apiRoles, err := o.client.GetAPIRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to list API roles: %w", err)
+	}
+
+	var rv []*v2.Grant
+	for i := range apiRoles {
+		if !slices.Contains(apiRoles[i].Privileges, privilege.Id.Resource) {
+			continue
+		}
+
+		ar, err := apiRoleResource(&apiRoles[i], nil)
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, grant.NewGrant(
+			privilege,
+			memberEntitlement,
+			ar.Id,
+			grant.WithAnnotation(&v2.GrantExpandable{
+				EntitlementIds: []string{ent.NewEntitlementID(ar, memberEntitlement)},
+			}),
+		))
+	}
+	return rv, nil
+}
+
+// Grant assigns a role to a userAccount or group. A built-in privilege-set
+// role replaces the principal's privilege_set outright. An individual
+// privilege is added to the matching category of a Custom principal's
+// privileges. A principal on a built-in set is refused one: its Privileges
+// data carries no access (see matchesIndividualPrivilege), so switching it to
+// Custom would strip the access the set gave it.
+func (o *roleResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	role := entitlement.Resource.Id.Resource
+
+	privilegeSet, privileges, err := o.getPrivileges(ctx, principal.Id)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: grant role %q: %w", role, err)
+	}
+
+	if slices.Contains(privilegeSets, role) {
+		if privilegeSet == role {
+			return annotations.New(&v2.GrantAlreadyExists{}), nil
+		}
+		if err := o.setPrivileges(ctx, principal.Id, role, nil); err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to set privilege set %q on %s %s: %w", role, principal.Id.ResourceType, principal.Id.Resource, err)
+		}
+		return nil, nil
+	}
+
+	if matchesIndividualPrivilege(privilegeSet, privileges, role) {
+		return annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	if slices.Contains(privilegeSets, privilegeSet) {
+		return nil, fmt.Errorf("jamf-connector: %s %s has the %q privilege set; revoke it before granting individual privilege %q", principal.Id.ResourceType, principal.Id.Resource, privilegeSet, role)
+	}
+
+	// The privilege list from /api/v1/api-role-privileges is flat, so the
+	// category has to be inferred. Each candidate is written and then read
+	// back: Jamf rejects — or silently drops — a privilege sent under the
+	// wrong category, and a grant must not report success in that case.
+	for _, category := range privilegeCategories(role) {
+		updated := clonePrivileges(*privileges)
+		if err := updated.Add(category, role); err != nil {
+			return nil, err
+		}
+
+		err := o.setPrivileges(ctx, principal.Id, privilegeSetCustom, &updated)
+		if err != nil {
+			// The Classic API answers an update it can't apply with either
+			// 400 or 409 — both mean "try the next category" here.
+			if jamf.IsInvalidRequestError(err) || jamf.IsAlreadyExistsError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("jamf-connector: failed to grant privilege %q to %s %s: %w", role, principal.Id.ResourceType, principal.Id.Resource, err)
+		}
+
+		gotSet, gotPrivileges, err := o.getPrivileges(ctx, principal.Id)
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: grant role %q: failed to verify: %w", role, err)
+		}
+		if matchesIndividualPrivilege(gotSet, gotPrivileges, role) {
+			return nil, nil
+		}
+	}
+
+	return nil, fmt.Errorf("jamf-connector: Jamf did not accept privilege %q for %s %s in any privilege category", role, principal.Id.ResourceType, principal.Id.Resource)
+}
+
+// Revoke removes a role from a userAccount or group. Revoking the principal's
+// built-in privilege set leaves it on Custom with no privileges — Jamf has
+// no "no privilege set" value. Revoking an individual privilege removes it
+// from the principal's Custom privileges.
+func (o *roleResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	principal := grant.Principal
+	role := grant.Entitlement.Resource.Id.Resource
+
+	privilegeSet, privileges, err := o.getPrivileges(ctx, principal.Id)
+	if err != nil {
+		if jamf.IsNotFoundError(err) {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		return nil, fmt.Errorf("jamf-connector: revoke role %q: %w", role, err)
+	}
+
+	if slices.Contains(privilegeSets, role) {
+		if privilegeSet != role {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		if err := o.setPrivileges(ctx, principal.Id, privilegeSetCustom, &jamf.Privileges{}); err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to revoke privilege set %q from %s %s: %w", role, principal.Id.ResourceType, principal.Id.Resource, err)
+		}
+		return nil, nil
+	}
+
+	if !matchesIndividualPrivilege(privilegeSet, privileges, role) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	updated := clonePrivileges(*privileges)
+	updated.Remove(role)
+	if err := o.setPrivileges(ctx, principal.Id, privilegeSetCustom, &updated); err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to revoke privilege %q from %s %s: %w", role, principal.Id.ResourceType, principal.Id.Resource, err)
+	}
+
+	return nil, nil
+}
+
+// getPrivileges returns the privilege set and privileges currently held by a
+// userAccount or group principal.
+func (o *roleResourceType) getPrivileges(ctx context.Context, principalId *v2.ResourceId) (string, *jamf.Privileges, error) {
+	id, err := strconv.Atoi(principalId.Resource)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid %s id %q: %w", principalId.ResourceType, principalId.Resource, err)
+	}
+
+	switch principalId.ResourceType {
+	case resourceTypeUserAccount.Id:
+		account, err := o.client.GetUserAccountDetails(ctx, id)
+		if err != nil {
+			return "", nil, err
+		}
+		return account.PrivilegeSet, &account.Privileges, nil
+	case resourceTypeGroup.Id:
+		group, err := o.client.GetGroupDetails(ctx, id)
+		if err != nil {
+			return "", nil, err
+		}
+		return group.PrivilegeSet, &group.Privileges, nil
+	default:
+		return "", nil, fmt.Errorf("roles can only be granted to user accounts and groups, got %s", principalId.ResourceType)
+	}
+}
+
+// setPrivileges writes privilegeSet, and privileges when non-nil, to a
+// userAccount or group principal.
+func (o *roleResourceType) setPrivileges(ctx context.Context, principalId *v2.ResourceId, privilegeSet string, privileges *jamf.Privileges) error {
+	id, err := strconv.Atoi(principalId.Resource)
+	if err != nil {
+		return fmt.Errorf("invalid %s id %q: %w", principalId.ResourceType, principalId.Resource, err)
+	}
+
+	switch principalId.ResourceType {
+	case resourceTypeUserAccount.Id:
+		return o.client.UpdateUserAccount(ctx, id, jamf.UserAccountUpdateBody{
+			PrivilegeSet: privilegeSet,
+			Privileges:   (*jamf.ReplacementPrivileges)(privileges),
+		})
+	case resourceTypeGroup.Id:
+		return o.client.UpdateGroup(ctx, id, jamf.GroupUpdateBody{
+			PrivilegeSet: privilegeSet,
+			Privileges:   (*jamf.ReplacementPrivileges)(privileges),
+		})
+	default:
+		return fmt.Errorf("roles can only be granted to user accounts and groups, got %s", principalId.ResourceType)
+	}
+}
+
+// privilegeCategories returns the Classic API privilege categories an
+// individual privilege most likely belongs to, best guess first. Jamf names
+// its Casper suite privileges after the app, and object CRUD privileges
+// "Create/Read/Update/Delete <object>"; settings share the Read/Update verbs,
+// so those fall back to jss_settings. Anything else is most often an action.
+func privilegeCategories(privilege string) []string {
+	switch {
+	case strings.Contains(privilege, "Casper Admin"):
+		return []string{jamf.PrivilegeCategoryCasperAdmin}
+	case strings.Contains(privilege, "Casper Remote"):
+		return []string{jamf.PrivilegeCategoryCasperRemote}
+	case strings.Contains(privilege, "Casper Imaging"):
+		return []string{jamf.PrivilegeCategoryCasperImaging}
+	case strings.HasPrefix(privilege, "Read "), strings.HasPrefix(privilege, "Update "):
+		return []string{jamf.PrivilegeCategoryJSSObjects, jamf.PrivilegeCategoryJSSSettings, jamf.PrivilegeCategoryRecon}
+	case strings.HasPrefix(privilege, "Create "), strings.HasPrefix(privilege, "Delete "):
+		return []string{jamf.PrivilegeCategoryJSSObjects, jamf.PrivilegeCategoryRecon}
+	default:
+		return []string{jamf.PrivilegeCategoryJSSActions, jamf.PrivilegeCategoryRecon}
+	}
+}
+
+// clonePrivileges deep-copies p so edits don't alias the fetched record.
+func clonePrivileges(p jamf.Privileges) jamf.Privileges {
+	return jamf.Privileges{
+		JSSObjects:    slices.Clone(p.JSSObjects),
+		JSSSettings:   slices.Clone(p.JSSSettings),
+		JSSActions:    slices.Clone(p.JSSActions),
+		Recon:         slices.Clone(p.Recon),
+		CasperAdmin:   slices.Clone(p.CasperAdmin),
+		CasperRemote:  slices.Clone(p.CasperRemote),
+		CasperImaging: slices.Clone(p.CasperImaging),
+	}
+}
+
+func roleBuilder(client *jamf.Client, syncAPIRoles bool) *roleResourceType {
 	return &roleResourceType{
 		resourceType: resourceTypeRole,
 		client:       client,
+		syncAPIRoles: syncAPIRoles,
 	}
 }
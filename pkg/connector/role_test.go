@@ -1,11 +1,99 @@
 package connector
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 )
 
+// fakePrivilegeRecord is the privilege state of one account or group.
+type fakePrivilegeRecord struct {
+	privilegeSet string
+	privileges   jamf.Privileges
+}
+
+// fakePrivileges serves GET and PUT on /JSSResource/accounts/userid/{id} and
+// /JSSResource/accounts/groupid/{id} from the privileges of each record,
+// keyed by "userid/{id}" or "groupid/{id}", and counts the PUTs. A PUT naming
+// a privilege under a category in rejected is answered with that status; one
+// under a category in dropped is accepted but not stored, the way Jamf drops
+// privileges it doesn't know in that category.
+type fakePrivileges struct {
+	mu       sync.Mutex
+	records  map[string]*fakePrivilegeRecord
+	rejected map[string]int
+	dropped  []string
+	puts     int
+}
+
+func (f *fakePrivileges) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/JSSResource/accounts/")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	record, ok := f.records[key]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(key, "groupid/") {
+			_ = json.NewEncoder(w).Encode(jamf.GroupResponse{Group: jamf.Group{PrivilegeSet: record.privilegeSet, Privileges: record.privileges}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(jamf.UserAccountResponse{UserAccount: jamf.UserAccount{PrivilegeSet: record.privilegeSet, Privileges: record.privileges}})
+	case http.MethodPut:
+		var body struct {
+			PrivilegeSet string                      `xml:"privilege_set"`
+			Privileges   *jamf.ReplacementPrivileges `xml:"privileges"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+		f.puts++
+		if body.Privileges != nil {
+			privileges := jamf.Privileges(*body.Privileges)
+			for category, code := range f.rejected {
+				if len(*categoryItems(&privileges, category)) > 0 {
+					w.WriteHeader(code)
+					return
+				}
+			}
+			for _, category := range f.dropped {
+				*categoryItems(&privileges, category) = nil
+			}
+			record.privileges = privileges
+		}
+		record.privilegeSet = body.PrivilegeSet
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// categoryItems returns the privileges listed under one of the categories
+// the tests use, as a pointer so callers can clear it.
+func categoryItems(p *jamf.Privileges, category string) *[]string {
+	return map[string]*[]string{
+		jamf.PrivilegeCategoryJSSObjects:  &p.JSSObjects,
+		jamf.PrivilegeCategoryJSSSettings: &p.JSSSettings,
+		jamf.PrivilegeCategoryJSSActions:  &p.JSSActions,
+		jamf.PrivilegeCategoryRecon:       &p.Recon,
+	}[category]
+}
+
 // TestMatchesIndividualPrivilege_CustomOnly guards against PR #28 review
 // feedback: widening Privileges.Contains to all 7 categories must not grant
 // individual-privilege roles to built-in-privilege-set accounts, even if
@@ -38,3 +126,208 @@ func TestMatchesIndividualPrivilege_CustomOnly(t *testing.T) {
 		})
 	}
 }
+
+func TestPrivilegeCategories(t *testing.T) {
+	tests := []struct {
+		privilege string
+		want      string
+	}{
+		{"Read User", jamf.PrivilegeCategoryJSSObjects},
+		{"Delete Computers", jamf.PrivilegeCategoryJSSObjects},
+		{"Send Computer Remote Lock Command", jamf.PrivilegeCategoryJSSActions},
+		{"Use Casper Admin", jamf.PrivilegeCategoryCasperAdmin},
+		{"Use Casper Remote", jamf.PrivilegeCategoryCasperRemote},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.privilege, func(t *testing.T) {
+			got := privilegeCategories(tt.privilege)
+			if len(got) == 0 || got[0] != tt.want {
+				t.Errorf("privilegeCategories(%q) = %v, want %q first", tt.privilege, got, tt.want)
+			}
+		})
+	}
+}
+
+func roleEntitlement(role string) *v2.Entitlement {
+	return &v2.Entitlement{Resource: &v2.Resource{Id: &v2.ResourceId{ResourceType: resourceTypeRole.Id, Resource: role}}}
+}
+
+func privilegePrincipal(resourceType *v2.ResourceType, id string) *v2.Resource {
+	return &v2.Resource{Id: &v2.ResourceId{ResourceType: resourceType.Id, Resource: id}}
+}
+
+func TestRoleGrantAndRevoke_IndividualPrivilege(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakePrivileges{records: map[string]*fakePrivilegeRecord{
+		"userid/101": {privilegeSet: privilegeSetCustom, privileges: jamf.Privileges{JSSObjects: []string{"Read Computers"}}},
+	}}
+	r := roleBuilder(newTestClient(t, fake), false)
+	account := privilegePrincipal(resourceTypeUserAccount, "101")
+	entitlement := roleEntitlement("Read Users")
+	grant := &v2.Grant{Principal: account, Entitlement: entitlement}
+
+	annos, err := r.Grant(ctx, account, entitlement)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hasAnnotation(annos, &v2.GrantAlreadyExists{}) {
+		t.Fatal("expected the first grant to add the privilege")
+	}
+	record := fake.records["userid/101"]
+	if record.privilegeSet != privilegeSetCustom || !slices.Equal(record.privileges.JSSObjects, []string{"Read Computers", "Read Users"}) {
+		t.Fatalf("expected the account to keep Read Computers and gain Read Users, got %q %v", record.privilegeSet, record.privileges.JSSObjects)
+	}
+
+	annos, err = r.Grant(ctx, account, entitlement)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasAnnotation(annos, &v2.GrantAlreadyExists{}) || fake.puts != 1 {
+		t.Fatalf("expected the second grant to be reported as already existing without an update, got %d updates", fake.puts)
+	}
+
+	annos, err = r.Revoke(ctx, grant)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) || !slices.Equal(record.privileges.JSSObjects, []string{"Read Computers"}) {
+		t.Fatalf("expected the revoke to remove only Read Users, got %v", record.privileges.JSSObjects)
+	}
+
+	annos, err = r.Revoke(ctx, grant)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) || fake.puts != 2 {
+		t.Fatalf("expected the second revoke to be reported as already revoked without an update, got %d updates", fake.puts)
+	}
+
+	annos, err = r.Revoke(ctx, &v2.Grant{Principal: privilegePrincipal(resourceTypeUserAccount, "999"), Entitlement: entitlement})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) {
+		t.Error("expected revoking from a missing account to be reported as already revoked")
+	}
+}
+
+// TestRoleGrant_TriesTheNextCategory checks that a privilege Jamf refuses, or
+// accepts but drops, under one category is tried under the next, and that the
+// grant fails once every category has been tried.
+func TestRoleGrant_TriesTheNextCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		rejected map[string]int
+		dropped  []string
+		want     string
+		wantPuts int
+	}{
+		{
+			name:     "rejected with 400 and 409",
+			rejected: map[string]int{jamf.PrivilegeCategoryJSSObjects: http.StatusBadRequest, jamf.PrivilegeCategoryJSSSettings: http.StatusConflict},
+			want:     jamf.PrivilegeCategoryRecon,
+			wantPuts: 3,
+		},
+		{
+			name:     "dropped on read back",
+			dropped:  []string{jamf.PrivilegeCategoryJSSObjects},
+			want:     jamf.PrivilegeCategoryJSSSettings,
+			wantPuts: 2,
+		},
+		{
+			name:     "never kept",
+			dropped:  []string{jamf.PrivilegeCategoryJSSObjects, jamf.PrivilegeCategoryJSSSettings, jamf.PrivilegeCategoryRecon},
+			wantPuts: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakePrivileges{
+				records:  map[string]*fakePrivilegeRecord{"groupid/201": {privilegeSet: privilegeSetCustom}},
+				rejected: tt.rejected,
+				dropped:  tt.dropped,
+			}
+			r := roleBuilder(newTestClient(t, fake), false)
+
+			_, err := r.Grant(context.Background(), privilegePrincipal(resourceTypeGroup, "201"), roleEntitlement("Read Users"))
+			if fake.puts != tt.wantPuts {
+				t.Errorf("expected %d updates, got %d", tt.wantPuts, fake.puts)
+			}
+			if tt.want == "" {
+				if err == nil {
+					t.Fatal("expected the grant to fail when Jamf keeps the privilege in no category")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := *categoryItems(&fake.records["groupid/201"].privileges, tt.want); !slices.Equal(got, []string{"Read Users"}) {
+				t.Errorf("expected Read Users under %s, got %+v", tt.want, fake.records["groupid/201"].privileges)
+			}
+		})
+	}
+}
+
+// TestRoleGrant_RefusesBuiltInSetPrincipal checks that an individual
+// privilege isn't granted to a principal on a built-in privilege set, which
+// switching to Custom would strip of the set's access.
+func TestRoleGrant_RefusesBuiltInSetPrincipal(t *testing.T) {
+	fake := &fakePrivileges{records: map[string]*fakePrivilegeRecord{
+		"userid/101": {privilegeSet: privilegeSetAdministrator},
+	}}
+	r := roleBuilder(newTestClient(t, fake), false)
+
+	_, err := r.Grant(context.Background(), privilegePrincipal(resourceTypeUserAccount, "101"), roleEntitlement("Read Users"))
+	if err == nil || !strings.Contains(err.Error(), privilegeSetAdministrator) {
+		t.Fatalf("expected the grant to be refused naming the Administrator set, got %v", err)
+	}
+	if fake.puts != 0 || fake.records["userid/101"].privilegeSet != privilegeSetAdministrator {
+		t.Errorf("expected the account to be left on Administrator, got %q after %d updates", fake.records["userid/101"].privilegeSet, fake.puts)
+	}
+}
+
+func TestRoleGrantAndRevoke_PrivilegeSet(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakePrivileges{records: map[string]*fakePrivilegeRecord{
+		"groupid/201": {privilegeSet: privilegeSetCustom, privileges: jamf.Privileges{JSSObjects: []string{"Read Computers"}}},
+	}}
+	r := roleBuilder(newTestClient(t, fake), false)
+	group := privilegePrincipal(resourceTypeGroup, "201")
+	entitlement := roleEntitlement(privilegeSetAuditor)
+	record := fake.records["groupid/201"]
+
+	annos, err := r.Grant(ctx, group, entitlement)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hasAnnotation(annos, &v2.GrantAlreadyExists{}) || record.privilegeSet != privilegeSetAuditor {
+		t.Fatalf("expected the group to move to Auditor, got %q", record.privilegeSet)
+	}
+
+	annos, err = r.Grant(ctx, group, entitlement)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasAnnotation(annos, &v2.GrantAlreadyExists{}) || fake.puts != 1 {
+		t.Fatalf("expected the second grant to be reported as already existing without an update, got %d updates", fake.puts)
+	}
+
+	annos, err = r.Revoke(ctx, &v2.Grant{Principal: group, Entitlement: roleEntitlement(privilegeSetAdministrator)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) || fake.puts != 1 {
+		t.Fatalf("expected revoking a set the group isn't on to be reported as already revoked, got %d updates", fake.puts)
+	}
+
+	annos, err = r.Revoke(ctx, &v2.Grant{Principal: group, Entitlement: entitlement})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) || record.privilegeSet != privilegeSetCustom || !record.privileges.IsEmpty() {
+		t.Fatalf("expected the revoke to leave the group on Custom with no privileges, got %q %+v", record.privilegeSet, record.privileges)
+	}
+}
@@ -0,0 +1,296 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// scopedEntitlement links a policy or configuration profile to the devices,
+// device groups, buildings and departments it's scoped to.
+const scopedEntitlement = "scoped"
+
+// scopeTargets are the targets a device scope (or its exclusions) lists.
+// Computer and mobile device scopes differ only in the kind of device and
+// device group they name, so both are mapped onto it.
+type scopeTargets struct {
+	devices     []jamf.BaseType
+	groups      []jamf.BaseType
+	buildings   []jamf.BaseType
+	departments []jamf.BaseType
+}
+
+func (t *scopeTargets) targetsLocations() bool {
+	return len(t.buildings) > 0 || len(t.departments) > 0
+}
+
+// deviceScope is the scope of a policy or configuration profile. A device is
+// in scope when all is set or it matches one of the targets, unless it
+// matches one of the exclusions.
+type deviceScope struct {
+	// phase is devicePhaseComputer or devicePhaseMobile.
+	phase      string
+	all        bool
+	targets    scopeTargets
+	exclusions scopeTargets
+}
+
+func computerScope(scope *jamf.ComputerScope) *deviceScope {
+	targets := func(t *jamf.ComputerScopeTargets) scopeTargets {
+		return scopeTargets{devices: t.Computers, groups: t.ComputerGroups, buildings: t.Buildings, departments: t.Departments}
+	}
+	return &deviceScope{
+		phase:      devicePhaseComputer,
+		all:        scope.AllComputers,
+		targets:    targets(&scope.ComputerScopeTargets),
+		exclusions: targets(&scope.Exclusions),
+	}
+}
+
+func mobileDeviceScope(scope *jamf.MobileDeviceScope) *deviceScope {
+	targets := func(t *jamf.MobileDeviceScopeTargets) scopeTargets {
+		return scopeTargets{devices: t.MobileDevices, groups: t.MobileDeviceGroups, buildings: t.Buildings, departments: t.Departments}
+	}
+	return &deviceScope{
+		phase:      devicePhaseMobile,
+		all:        scope.AllMobileDevices,
+		targets:    targets(&scope.MobileDeviceScopeTargets),
+		exclusions: targets(&scope.Exclusions),
+	}
+}
+
+// scopeGrants builds the grants of scope on resource's entitlement (scoped,
+// or assigned for apps): one for every device the scope reaches once its
+// exclusions are applied, and one for every device group, building and
+// department it names that isn't excluded outright.
+//
+// The device group grants aren't expandable: an excluded device can still be
+// a member of a scoped group, so devices are only ever granted directly.
+func scopeGrants(ctx context.Context, client *jamf.Client, resource *v2.Resource, entitlement string, scope *deviceScope) ([]*v2.Grant, error) {
+	deviceIds, err := resolveScope(ctx, client, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	var rv []*v2.Grant
+	for _, id := range deviceIds {
+		deviceId, err := rs.NewResourceID(resourceTypeManagedDevice, deviceObjectID(scope.phase, strconv.Itoa(id)))
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, grant.NewGrant(resource, entitlement, deviceId))
+	}
+
+	groupType := resourceTypeComputerGroup
+	if scope.phase == devicePhaseMobile {
+		groupType = resourceTypeMobileDeviceGroup
+	}
+	for _, target := range []struct {
+		resourceType *v2.ResourceType
+		included     []jamf.BaseType
+		excluded     []jamf.BaseType
+	}{
+		{groupType, scope.targets.groups, scope.exclusions.groups},
+		{resourceTypeBuilding, scope.targets.buildings, scope.exclusions.buildings},
+		{resourceTypeDepartment, scope.targets.departments, scope.exclusions.departments},
+	} {
+		for _, included := range target.included {
+			if containsID(target.excluded, included.ID) {
+				continue
+			}
+			principal, err := rs.NewResourceID(target.resourceType, included.ID)
+			if err != nil {
+				return nil, err
+			}
+			rv = append(rv, grant.NewGrant(resource, entitlement, principal))
+		}
+	}
+
+	return rv, nil
+}
+
+// resolveScope fetches what devicesInScope needs to resolve scope: the
+// device groups when it names any, and the device locations when it targets
+// all devices, a building or a department.
+func resolveScope(ctx context.Context, client *jamf.Client, scope *deviceScope) ([]int, error) {
+	var (
+		members   map[int][]int
+		locations []deviceLocation
+		err       error
+	)
+	if len(scope.targets.groups) > 0 || len(scope.exclusions.groups) > 0 {
+		members, err = deviceGroupMembers(ctx, client, scope.phase)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if scope.all || scope.targets.targetsLocations() || scope.exclusions.targetsLocations() {
+		locations, err = deviceLocations(ctx, client, scope.phase)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return devicesInScope(scope, members, locations), nil
+}
+
+// deviceGroupMembers returns the IDs of the members of every computer or
+// mobile device group, by group ID.
+func deviceGroupMembers(ctx context.Context, client *jamf.Client, phase string) (map[int][]int, error) {
+	rv := make(map[int][]int)
+	if phase == devicePhaseMobile {
+		groups, err := client.GetMobileDeviceGroups(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to list mobile device groups: %w", err)
+		}
+		for _, group := range groups {
+			for _, device := range group.MobileDevices {
+				rv[group.ID] = append(rv[group.ID], device.ID)
+			}
+		}
+		return rv, nil
+	}
+
+	groups, err := client.GetComputerGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to list computer groups: %w", err)
+	}
+	for _, group := range groups {
+		for _, computer := range group.Computers {
+			rv[group.ID] = append(rv[group.ID], computer.ID)
+		}
+	}
+	return rv, nil
+}
+
+// devicesInScope returns the IDs of the devices a scope reaches, in
+// ascending order. Device groups are expanded to their members, and
+// buildings and departments to the devices located there, so exclusions of
+// any kind can be subtracted.
+func devicesInScope(scope *deviceScope, members map[int][]int, locations []deviceLocation) []int {
+	included := devicesTargeted(&scope.targets, members, locations)
+	if scope.all {
+		for _, location := range locations {
+			included[location.id] = true
+		}
+	}
+	excluded := devicesTargeted(&scope.exclusions, members, locations)
+
+	var rv []int
+	for id := range included {
+		if !excluded[id] {
+			rv = append(rv, id)
+		}
+	}
+	slices.Sort(rv)
+	return rv
+}
+
+// devicesTargeted returns the IDs of the devices targets names directly,
+// through a device group, or through the building or department the device
+// is in. A group deleted since the scope was read has no members to add.
+func devicesTargeted(targets *scopeTargets, members map[int][]int, locations []deviceLocation) map[int]bool {
+	rv := make(map[int]bool)
+	for _, device := range targets.devices {
+		rv[device.ID] = true
+	}
+	for _, group := range targets.groups {
+		for _, id := range members[group.ID] {
+			rv[id] = true
+		}
+	}
+	if !targets.targetsLocations() {
+		return rv
+	}
+	for _, location := range locations {
+		if containsStringID(targets.buildings, location.buildingID) || containsStringID(targets.departments, location.departmentID) {
+			rv[location.id] = true
+		}
+	}
+	return rv
+}
+
+// userScopeGrants builds the grants of a user scope on resource's
+// entitlement: one for every Jamf user it names, or for every user when all
+// is set, and one for every user group it names, leaving out what
+// exclusions names. A user in an excluded user group is left out too, which
+// is why the user group grants aren't expandable either.
+//
+// Scopes name users by username, which is resolved through the user index,
+// so a user deleted since the scope was saved isn't granted.
+func userScopeGrants(
+	resource *v2.Resource,
+	entitlement string,
+	all bool,
+	targets *jamf.UserScopeTargets,
+	exclusions *jamf.UserScopeTargets,
+	users []*jamf.User,
+	userGroups []*jamf.UserGroup,
+) ([]*v2.Grant, error) {
+	userIndex := newUserIndex(users)
+
+	excluded := make(map[string]bool)
+	for _, user := range exclusions.JSSUsers {
+		if userId, ok := resolveUser(userIndex, user.Name, ""); ok {
+			excluded[userId.GetResource()] = true
+		}
+	}
+	for _, group := range userGroups {
+		if !containsID(exclusions.JSSUserGroups, group.ID) {
+			continue
+		}
+		for _, user := range group.Users {
+			excluded[strconv.Itoa(user.ID)] = true
+		}
+	}
+
+	var included []*v2.ResourceId
+	if all {
+		for _, user := range users {
+			userId, err := rs.NewResourceID(resourceTypeUser, user.ID)
+			if err != nil {
+				return nil, err
+			}
+			included = append(included, userId)
+		}
+	} else {
+		for _, user := range targets.JSSUsers {
+			if userId, ok := resolveUser(userIndex, user.Name, ""); ok {
+				included = append(included, userId)
+			}
+		}
+	}
+
+	var rv []*v2.Grant
+	for _, userId := range included {
+		if !excluded[userId.GetResource()] {
+			rv = append(rv, grant.NewGrant(resource, entitlement, userId))
+		}
+	}
+	for _, group := range targets.JSSUserGroups {
+		if containsID(exclusions.JSSUserGroups, group.ID) {
+			continue
+		}
+		groupId, err := rs.NewResourceID(resourceTypeUserGroup, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, grant.NewGrant(resource, entitlement, groupId))
+	}
+	return rv, nil
+}
+
+func containsID(refs []jamf.BaseType, id int) bool {
+	return slices.ContainsFunc(refs, func(ref jamf.BaseType) bool { return ref.ID == id })
+}
+
+// containsStringID is containsID for the string IDs the Jamf Pro API uses.
+func containsStringID(refs []jamf.BaseType, id string) bool {
+	return id != "" && slices.ContainsFunc(refs, func(ref jamf.BaseType) bool { return strconv.Itoa(ref.ID) == id })
+}
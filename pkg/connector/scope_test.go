@@ -0,0 +1,94 @@
+package connector
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+)
+
+func refs(ids ...int) []jamf.BaseType {
+	rv := make([]jamf.BaseType, 0, len(ids))
+	for _, id := range ids {
+		rv = append(rv, jamf.BaseType{ID: id})
+	}
+	return rv
+}
+
+func TestDevicesInScope(t *testing.T) {
+	members := map[int][]int{
+		401: {1, 2},
+		402: {2},
+	}
+	locations := []deviceLocation{
+		{id: 1, buildingID: "10", departmentID: "20"},
+		{id: 2, buildingID: "11"},
+		{id: 3, departmentID: "20"},
+		{id: 4},
+	}
+
+	tests := []struct {
+		name  string
+		scope jamf.ComputerScope
+		want  []int
+	}{
+		{
+			name:  "explicit computers",
+			scope: jamf.ComputerScope{ComputerScopeTargets: jamf.ComputerScopeTargets{Computers: refs(3, 1)}},
+			want:  []int{1, 3},
+		},
+		{
+			name: "group minus excluded computer",
+			scope: jamf.ComputerScope{
+				ComputerScopeTargets: jamf.ComputerScopeTargets{ComputerGroups: refs(401)},
+				Exclusions:           jamf.ComputerScopeTargets{Computers: refs(2)},
+			},
+			want: []int{1},
+		},
+		{
+			name: "all computers minus excluded group",
+			scope: jamf.ComputerScope{
+				AllComputers: true,
+				Exclusions:   jamf.ComputerScopeTargets{ComputerGroups: refs(402)},
+			},
+			want: []int{1, 3, 4},
+		},
+		{
+			name: "department minus excluded building",
+			scope: jamf.ComputerScope{
+				ComputerScopeTargets: jamf.ComputerScopeTargets{Departments: refs(20)},
+				Exclusions:           jamf.ComputerScopeTargets{Buildings: refs(10)},
+			},
+			want: []int{3},
+		},
+		{
+			name:  "deleted group reaches nothing",
+			scope: jamf.ComputerScope{ComputerScopeTargets: jamf.ComputerScopeTargets{ComputerGroups: refs(499)}},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := devicesInScope(computerScope(&tt.scope), members, locations); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMobileDeviceScope_MapsTargets(t *testing.T) {
+	scope := mobileDeviceScope(&jamf.MobileDeviceScope{
+		AllMobileDevices:         true,
+		MobileDeviceScopeTargets: jamf.MobileDeviceScopeTargets{MobileDevices: refs(7)},
+		Exclusions:               jamf.MobileDeviceScopeTargets{MobileDeviceGroups: refs(501), Departments: refs(20)},
+	})
+	if scope.phase != devicePhaseMobile {
+		t.Fatalf("expected the mobile device phase, got %q", scope.phase)
+	}
+
+	members := map[int][]int{501: {1}}
+	locations := []deviceLocation{{id: 1}, {id: 2}, {id: 3, departmentID: "20"}}
+	if got, want := devicesInScope(scope, members, locations), []int{2, 7}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
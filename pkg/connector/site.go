@@ -3,15 +3,25 @@ package connector
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 
 	"github.com/conductorone/baton-jamf/pkg/jamf"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
 	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
 )
 
+// Access levels of admin accounts and groups that tie them to sites. A
+// Site Access account or group must belong to exactly one site; a Group
+// Access account takes its sites from its groups and has none of its own.
+const (
+	accessLevelSiteAccess  = "Site Access"
+	accessLevelGroupAccess = "Group Access"
+)
+
 type siteResourceType struct {
 	resourceType *v2.ResourceType
 	client       *jamf.Client
@@ -36,13 +46,19 @@ func siteResource(site *jamf.Site, parentResourceID *v2.ResourceId) (*v2.Resourc
 }
 
 func (g *siteResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
-	sites, err := g.client.GetSites(ctx)
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	bag, offset, pageSize, err := parseOffsetPageToken(attrs.PageToken, g.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sites, total, err := g.client.GetSitesPage(ctx, offset, pageSize)
 	if err != nil {
 		return nil, nil, fmt.Errorf("jamf-connector: failed to list sites: %w", err)
 	}
 
 	var rv []*v2.Resource
-	for _, site := range *sites {
+	for _, site := range sites {
 		siteCopy := site
 		ur, err := siteResource(&siteCopy, parentId)
 		if err != nil {
@@ -51,14 +67,19 @@ func (g *siteResourceType) List(ctx context.Context, parentId *v2.ResourceId, at
 		rv = append(rv, ur)
 	}
 
-	return rv, nil, nil
+	nextToken, err := nextOffsetPageToken(bag, offset+pageSize, total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rv, &rs.SyncOpResults{NextPageToken: nextToken}, nil
 }
 
 func (g *siteResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
 	var rv []*v2.Entitlement
 
 	assigmentOptions := []ent.EntitlementOption{
-		ent.WithGrantableTo(resourceTypeUser),
+		ent.WithGrantableTo(resourceTypeUser, resourceTypeUserGroup, resourceTypeUserAccount, resourceTypeGroup),
 		ent.WithDescription(fmt.Sprintf("Member of %s Site in Jamf", resource.DisplayName)),
 		ent.WithDisplayName(fmt.Sprintf("%s Site %s", resource.DisplayName, memberEntitlement)),
 	}
@@ -70,6 +91,7 @@ func (g *siteResourceType) Entitlements(_ context.Context, resource *v2.Resource
 }
 
 func (g *siteResourceType) Grants(ctx context.Context, resource *v2.Resource, attrs rs.SyncOpAttrs) ([]*v2.Grant, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
 	var rv []*v2.Grant
 
 	users, err := g.client.GetUsers(ctx)
@@ -144,6 +166,213 @@ func (g *siteResourceType) Grants(ctx context.Context, resource *v2.Resource, at
 	return rv, nil, nil
 }
 
+// Grant adds a principal to a site. Users can belong to any number of sites;
+// user groups, admin accounts and admin groups belong to at most one, so
+// granting a second site to those is refused rather than silently moving
+// them off the first.
+func (g *siteResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	siteId, principalId, err := siteMembershipIDs(principal, entitlement)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: grant site membership: %w", err)
+	}
+
+	switch principal.Id.ResourceType {
+	case resourceTypeUser.Id:
+		user, err := g.client.GetUserDetails(ctx, principalId)
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: grant site membership: failed to get user %d: %w", principalId, err)
+		}
+		siteIds := userSiteIDs(user)
+		if slices.Contains(siteIds, siteId) {
+			return annotations.New(&v2.GrantAlreadyExists{}), nil
+		}
+		err = g.client.UpdateUser(ctx, principalId, jamf.UserUpdateBody{
+			Sites: jamf.NewUserSites(append(siteIds, siteId)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to add user %d to site %d: %w", principalId, siteId, err)
+		}
+
+	case resourceTypeUserGroup.Id:
+		userGroup, err := g.client.GetUserGroupDetails(ctx, principalId)
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: grant site membership: failed to get user group %d: %w", principalId, err)
+		}
+		if err := checkSingleSiteGrant("user group", userGroup.Name, userGroup.Site.BaseType, siteId); err != nil {
+			return nil, err
+		}
+		if userGroup.Site.ID == siteId {
+			return annotations.New(&v2.GrantAlreadyExists{}), nil
+		}
+		err = g.client.UpdateUserGroup(ctx, principalId, jamf.UserGroupUpdateBody{Site: &jamf.IDRef{ID: siteId}})
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to add user group %d to site %d: %w", principalId, siteId, err)
+		}
+
+	case resourceTypeUserAccount.Id:
+		account, err := g.client.GetUserAccountDetails(ctx, principalId)
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: grant site membership: failed to get account %d: %w", principalId, err)
+		}
+		if account.AccessLevel == accessLevelGroupAccess {
+			return nil, fmt.Errorf("jamf-connector: account %q has Group Access and takes its sites from its groups; grant the site to one of its groups instead", account.Name)
+		}
+		if err := checkSingleSiteGrant("account", account.Name, account.Site, siteId); err != nil {
+			return nil, err
+		}
+		if account.Site.ID == siteId {
+			return annotations.New(&v2.GrantAlreadyExists{}), nil
+		}
+		err = g.client.UpdateUserAccount(ctx, principalId, jamf.UserAccountUpdateBody{Site: &jamf.IDRef{ID: siteId}})
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to add account %d to site %d: %w", principalId, siteId, err)
+		}
+
+	case resourceTypeGroup.Id:
+		group, err := g.client.GetGroupDetails(ctx, principalId)
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: grant site membership: failed to get group %d: %w", principalId, err)
+		}
+		if err := checkSingleSiteGrant("group", group.Name, group.Site, siteId); err != nil {
+			return nil, err
+		}
+		if group.Site.ID == siteId {
+			return annotations.New(&v2.GrantAlreadyExists{}), nil
+		}
+		err = g.client.UpdateGroup(ctx, principalId, jamf.GroupUpdateBody{Site: &jamf.IDRef{ID: siteId}})
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to add group %d to site %d: %w", principalId, siteId, err)
+		}
+
+	default:
+		return nil, fmt.Errorf("jamf-connector: site membership can't be granted to %s", principal.Id.ResourceType)
+	}
+
+	return nil, nil
+}
+
+// Revoke removes a principal from a site. A single-site principal is moved
+// to no site at all, except for Site Access accounts and groups: Jamf
+// requires those to have a site, so their revoke is refused.
+func (g *siteResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	principal := grant.Principal
+	siteId, principalId, err := siteMembershipIDs(principal, grant.Entitlement)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: revoke site membership: %w", err)
+	}
+
+	noSite := &jamf.IDRef{ID: jamf.NoSiteID}
+	switch principal.Id.ResourceType {
+	case resourceTypeUser.Id:
+		user, err := g.client.GetUserDetails(ctx, principalId)
+		if err != nil {
+			if jamf.IsNotFoundError(err) {
+				return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+			}
+			return nil, fmt.Errorf("jamf-connector: revoke site membership: failed to get user %d: %w", principalId, err)
+		}
+		siteIds := userSiteIDs(user)
+		if !slices.Contains(siteIds, siteId) {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		err = g.client.UpdateUser(ctx, principalId, jamf.UserUpdateBody{
+			Sites: jamf.NewUserSites(slices.DeleteFunc(siteIds, func(id int) bool { return id == siteId })),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to remove user %d from site %d: %w", principalId, siteId, err)
+		}
+
+	case resourceTypeUserGroup.Id:
+		userGroup, err := g.client.GetUserGroupDetails(ctx, principalId)
+		if err != nil {
+			if jamf.IsNotFoundError(err) {
+				return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+			}
+			return nil, fmt.Errorf("jamf-connector: revoke site membership: failed to get user group %d: %w", principalId, err)
+		}
+		if userGroup.Site.ID != siteId {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		if err := g.client.UpdateUserGroup(ctx, principalId, jamf.UserGroupUpdateBody{Site: noSite}); err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to remove user group %d from site %d: %w", principalId, siteId, err)
+		}
+
+	case resourceTypeUserAccount.Id:
+		account, err := g.client.GetUserAccountDetails(ctx, principalId)
+		if err != nil {
+			if jamf.IsNotFoundError(err) {
+				return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+			}
+			return nil, fmt.Errorf("jamf-connector: revoke site membership: failed to get account %d: %w", principalId, err)
+		}
+		if account.Site.ID != siteId {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		if account.AccessLevel == accessLevelSiteAccess {
+			return nil, fmt.Errorf("jamf-connector: account %q has Site Access and must belong to a site; change its access level or site in Jamf instead", account.Name)
+		}
+		if err := g.client.UpdateUserAccount(ctx, principalId, jamf.UserAccountUpdateBody{Site: noSite}); err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to remove account %d from site %d: %w", principalId, siteId, err)
+		}
+
+	case resourceTypeGroup.Id:
+		group, err := g.client.GetGroupDetails(ctx, principalId)
+		if err != nil {
+			if jamf.IsNotFoundError(err) {
+				return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+			}
+			return nil, fmt.Errorf("jamf-connector: revoke site membership: failed to get group %d: %w", principalId, err)
+		}
+		if group.Site.ID != siteId {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		if group.AccessLevel == accessLevelSiteAccess {
+			return nil, fmt.Errorf("jamf-connector: group %q has Site Access and must belong to a site; change its access level or site in Jamf instead", group.Name)
+		}
+		if err := g.client.UpdateGroup(ctx, principalId, jamf.GroupUpdateBody{Site: noSite}); err != nil {
+			return nil, fmt.Errorf("jamf-connector: failed to remove group %d from site %d: %w", principalId, siteId, err)
+		}
+
+	default:
+		return nil, fmt.Errorf("jamf-connector: site membership can't be revoked from %s", principal.Id.ResourceType)
+	}
+
+	return nil, nil
+}
+
+// siteMembershipIDs parses the site and principal IDs of a site grant.
+func siteMembershipIDs(principal *v2.Resource, entitlement *v2.Entitlement) (int, int, error) {
+	siteId, err := strconv.Atoi(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid site id %q: %w", entitlement.Resource.Id.Resource, err)
+	}
+	principalId, err := strconv.Atoi(principal.Id.Resource)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid %s id %q: %w", principal.Id.ResourceType, principal.Id.Resource, err)
+	}
+	return siteId, principalId, nil
+}
+
+// checkSingleSiteGrant refuses to grant siteId to a principal that can only
+// belong to one site and already belongs to a different one.
+func checkSingleSiteGrant(kind string, name string, current jamf.BaseType, siteId int) error {
+	if current.ID <= 0 || current.ID == siteId {
+		return nil
+	}
+	return fmt.Errorf("jamf-connector: %s %q already belongs to site %q and can only belong to one site in Jamf; revoke that site first", kind, name, current.Name)
+}
+
+// userSiteIDs returns the IDs of the sites a user belongs to.
+func userSiteIDs(user *jamf.User) []int {
+	ids := make([]int, 0, len(user.Sites))
+	for _, site := range user.Sites {
+		ids = append(ids, site.Site.ID)
+	}
+	return ids
+}
+
 func siteBuilder(client *jamf.Client) *siteResourceType {
 	return &siteResourceType{
 		resourceType: resourceTypeSite,
@@ -0,0 +1,248 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+func TestCheckSingleSiteGrant(t *testing.T) {
+	headquarters := jamf.BaseType{ID: 1, Name: "Headquarters"}
+
+	tests := []struct {
+		name    string
+		current jamf.BaseType
+		siteId  int
+		wantErr bool
+	}{
+		{"no site yet", jamf.BaseType{}, 1, false},
+		{"none site", jamf.BaseType{ID: jamf.NoSiteID, Name: "None"}, 1, false},
+		{"same site", headquarters, 1, false},
+		{"different site must be refused", headquarters, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSingleSiteGrant("account", "admin1", tt.current, tt.siteId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkSingleSiteGrant(%+v, %d) error = %v, wantErr %v", tt.current, tt.siteId, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeSiteMembers serves GET and PUT on the Classic API records of users,
+// user groups, accounts and groups from the sites each belongs to, keyed by
+// path below /JSSResource/ (e.g. "users/id/1"), and records the PUT bodies.
+type fakeSiteMembers struct {
+	mu           sync.Mutex
+	sites        map[string][]int
+	accessLevels map[string]string
+	bodies       []string
+}
+
+func (f *fakeSiteMembers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/JSSResource/")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	siteIds, ok := f.sites[key]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		site := jamf.BaseType{ID: jamf.NoSiteID, Name: "None"}
+		if len(siteIds) > 0 {
+			site = jamf.BaseType{ID: siteIds[0], Name: "site"}
+		}
+		var response any
+		switch {
+		case strings.HasPrefix(key, "users/"):
+			user := jamf.User{}
+			for _, id := range siteIds {
+				user.Sites = append(user.Sites, struct {
+					Site jamf.BaseType `json:"site"`
+				}{Site: jamf.BaseType{ID: id}})
+			}
+			response = jamf.UserResponse{User: user}
+		case strings.HasPrefix(key, "usergroups/"):
+			response = jamf.UserGroupResponse{UserGroup: jamf.UserGroup{Site: jamf.Site{BaseType: site}}}
+		case strings.HasPrefix(key, "accounts/userid/"):
+			response = jamf.UserAccountResponse{UserAccount: jamf.UserAccount{AccessLevel: f.accessLevels[key], Site: site}}
+		default:
+			response = jamf.GroupResponse{Group: jamf.Group{AccessLevel: f.accessLevels[key], Site: site}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	case http.MethodPut:
+		raw, _ := io.ReadAll(r.Body)
+		var body struct {
+			Sites *jamf.UserSites `xml:"sites"`
+			Site  *jamf.IDRef     `xml:"site"`
+		}
+		if err := xml.Unmarshal(raw, &body); err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+		f.bodies = append(f.bodies, string(raw))
+		switch {
+		case body.Sites != nil:
+			f.sites[key] = f.sites[key][:0]
+			for _, site := range body.Sites.Sites {
+				f.sites[key] = append(f.sites[key], site.ID)
+			}
+		case body.Site != nil && body.Site.ID == jamf.NoSiteID:
+			f.sites[key] = []int{}
+		case body.Site != nil:
+			f.sites[key] = []int{body.Site.ID}
+		}
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestSiteMembershipGrantAndRevoke(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType *v2.ResourceType
+		key          string
+		sites        []int
+		wantGrant    string
+		wantRevoke   string
+	}{
+		{
+			name:         "user",
+			resourceType: resourceTypeUser,
+			key:          "users/id/1",
+			sites:        []int{2},
+			wantGrant:    "<user><sites><site><id>2</id></site><site><id>1</id></site></sites></user>",
+			wantRevoke:   "<user><sites><site><id>2</id></site></sites></user>",
+		},
+		{
+			name:         "user group",
+			resourceType: resourceTypeUserGroup,
+			key:          "usergroups/id/1",
+			wantGrant:    "<user_group><site><id>1</id></site></user_group>",
+			wantRevoke:   "<user_group><site><id>-1</id></site></user_group>",
+		},
+		{
+			name:         "user account",
+			resourceType: resourceTypeUserAccount,
+			key:          "accounts/userid/1",
+			wantGrant:    "<account><site><id>1</id></site></account>",
+			wantRevoke:   "<account><site><id>-1</id></site></account>",
+		},
+		{
+			name:         "group",
+			resourceType: resourceTypeGroup,
+			key:          "accounts/groupid/1",
+			wantGrant:    "<group><site><id>1</id></site></group>",
+			wantRevoke:   "<group><site><id>-1</id></site></group>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fake := &fakeSiteMembers{sites: map[string][]int{tt.key: tt.sites}}
+			s := siteBuilder(newTestClient(t, fake))
+			principal := &v2.Resource{Id: &v2.ResourceId{ResourceType: tt.resourceType.Id, Resource: "1"}}
+			entitlement := &v2.Entitlement{Resource: &v2.Resource{Id: &v2.ResourceId{ResourceType: resourceTypeSite.Id, Resource: "1"}}}
+			grant := &v2.Grant{Principal: principal, Entitlement: entitlement}
+
+			annos, err := s.Grant(ctx, principal, entitlement)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if hasAnnotation(annos, &v2.GrantAlreadyExists{}) || !slices.Equal(fake.bodies, []string{tt.wantGrant}) {
+				t.Fatalf("expected the grant to send %s, got %v", tt.wantGrant, fake.bodies)
+			}
+
+			annos, err = s.Grant(ctx, principal, entitlement)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !hasAnnotation(annos, &v2.GrantAlreadyExists{}) || len(fake.bodies) != 1 {
+				t.Fatalf("expected the second grant to be reported as already existing without an update, got %d updates", len(fake.bodies))
+			}
+
+			annos, err = s.Revoke(ctx, grant)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) || len(fake.bodies) != 2 || fake.bodies[1] != tt.wantRevoke {
+				t.Fatalf("expected the revoke to send %s, got %v", tt.wantRevoke, fake.bodies)
+			}
+
+			annos, err = s.Revoke(ctx, grant)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) || len(fake.bodies) != 2 {
+				t.Fatalf("expected the second revoke to be reported as already revoked without an update, got %d updates", len(fake.bodies))
+			}
+
+			missing := &v2.Resource{Id: &v2.ResourceId{ResourceType: tt.resourceType.Id, Resource: "999"}}
+			annos, err = s.Revoke(ctx, &v2.Grant{Principal: missing, Entitlement: entitlement})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) {
+				t.Errorf("expected revoking from a missing %s to be reported as already revoked", tt.name)
+			}
+		})
+	}
+}
+
+// TestSiteMembership_AccessLevelChecks checks that a site isn't granted to a
+// Group Access account, which takes its sites from its groups, or revoked
+// from a Site Access account or group, which Jamf requires to have a site.
+func TestSiteMembership_AccessLevelChecks(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeSiteMembers{
+		sites: map[string][]int{
+			"accounts/userid/1":  {},
+			"accounts/userid/2":  {1},
+			"accounts/groupid/3": {1},
+			"usergroups/id/4":    {2},
+		},
+		accessLevels: map[string]string{
+			"accounts/userid/1":  accessLevelGroupAccess,
+			"accounts/userid/2":  accessLevelSiteAccess,
+			"accounts/groupid/3": accessLevelSiteAccess,
+		},
+	}
+	s := siteBuilder(newTestClient(t, fake))
+	principal := func(resourceType *v2.ResourceType, id string) *v2.Resource {
+		return &v2.Resource{Id: &v2.ResourceId{ResourceType: resourceType.Id, Resource: id}}
+	}
+	entitlement := &v2.Entitlement{Resource: &v2.Resource{Id: &v2.ResourceId{ResourceType: resourceTypeSite.Id, Resource: "1"}}}
+
+	if _, err := s.Grant(ctx, principal(resourceTypeUserAccount, "1"), entitlement); err == nil || !strings.Contains(err.Error(), accessLevelGroupAccess) {
+		t.Errorf("expected granting a site to a Group Access account to be refused, got %v", err)
+	}
+	if _, err := s.Grant(ctx, principal(resourceTypeUserGroup, "4"), entitlement); err == nil {
+		t.Error("expected granting a second site to a user group to be refused")
+	}
+	for _, p := range []*v2.Resource{principal(resourceTypeUserAccount, "2"), principal(resourceTypeGroup, "3")} {
+		if _, err := s.Revoke(ctx, &v2.Grant{Principal: p, Entitlement: entitlement}); err == nil || !strings.Contains(err.Error(), accessLevelSiteAccess) {
+			t.Errorf("expected revoking the site of Site Access %s to be refused, got %v", p.Id.ResourceType, err)
+		}
+	}
+	if len(fake.bodies) != 0 {
+		t.Errorf("expected no updates, got %v", fake.bodies)
+	}
+}
@@ -72,7 +72,13 @@ func userResource(user *jamf.User, parentResourceID *v2.ResourceId) (*v2.Resourc
 }
 
 func (o *userResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
-	users, err := o.client.GetUsers(ctx)
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	bag, offset, pageSize, err := parseOffsetPageToken(attrs.PageToken, o.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	users, total, err := o.client.GetUsersPage(ctx, offset, pageSize)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -87,7 +93,12 @@ func (o *userResourceType) List(ctx context.Context, parentId *v2.ResourceId, at
 		rv = append(rv, ur)
 	}
 
-	return rv, nil, nil
+	nextToken, err := nextOffsetPageToken(bag, offset+pageSize, total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rv, &rs.SyncOpResults{NextPageToken: nextToken}, nil
 }
 
 func (o *userResourceType) Entitlements(_ context.Context, _ *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
@@ -3,6 +3,7 @@ package connector
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -17,6 +18,10 @@ import (
 type userAccountResourceType struct {
 	resourceType *v2.ResourceType
 	client       *jamf.Client
+
+	// disableOnDelete makes Delete disable the account rather than delete
+	// it (see config.DisableUserAccountsOnDeleteField).
+	disableOnDelete bool
 }
 
 // Valid values Jamf accepts for an admin account's privilege_set. See
@@ -107,9 +112,10 @@ const (
 	defaultAccessLevel  = "Full Access"
 	defaultPrivilegeSet = privilegeSetAuditor
 
-	// enabledValue is the Jamf Classic API's string representation of an
-	// enabled account (as opposed to "Disabled").
-	enabledValue = "Enabled"
+	// enabledValue and disabledValue are the Jamf Classic API's string
+	// representations of an enabled and a disabled account.
+	enabledValue  = "Enabled"
+	disabledValue = "Disabled"
 )
 
 func (o *userAccountResourceType) ResourceType(_ context.Context) *v2.ResourceType {
@@ -125,6 +131,7 @@ func userAccountResource(account *jamf.UserAccount, parentResourceID *v2.Resourc
 		"login":      account.Email,
 		"user_id":    fmt.Sprintf("account:%d", account.ID),
 	}
+	addAccountSource(profile, account.IsLDAP(), account.LDAPServer)
 
 	var resourceStatus v2.Status_ResourceStatus
 	if account.Enabled == enabledValue {
@@ -154,6 +161,7 @@ func userAccountResource(account *jamf.UserAccount, parentResourceID *v2.Resourc
 }
 
 func (o *userAccountResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
 	userAccounts, _, err := o.client.GetAccounts(ctx)
 	if err != nil {
 		return nil, nil, fmt.Errorf("jamf-connector: failed to list accounts: %w", err)
@@ -340,7 +348,8 @@ func (o *provisionableUserAccountType) CreateAccount(
 	return &v2.CreateAccountResponse_SuccessResult{Resource: resource}, plaintextData, nil, nil
 }
 
-// Delete removes a Jamf console admin account. Not gated by
+// Delete removes a Jamf console admin account, or disables it when the
+// connector is configured to keep deleted accounts. Not gated by
 // create-account-resource-type — deprovisioning works for both account types
 // regardless of which one is configured for creation.
 func (o *userAccountResourceType) Delete(ctx context.Context, resourceID *v2.ResourceId, _ *v2.ResourceId) (annotations.Annotations, error) {
@@ -349,7 +358,11 @@ func (o *userAccountResourceType) Delete(ctx context.Context, resourceID *v2.Res
 		return nil, fmt.Errorf("jamf-connector: delete userAccount: invalid resource id %q: %w", resourceID.Resource, err)
 	}
 
-	err = o.client.DeleteUserAccount(ctx, id)
+	if o.disableOnDelete {
+		err = o.client.UpdateUserAccount(ctx, id, jamf.UserAccountUpdateBody{Enabled: disabledValue})
+	} else {
+		err = o.client.DeleteUserAccount(ctx, id)
+	}
 	if err != nil {
 		if jamf.IsNotFoundError(err) {
 			return nil, nil
@@ -359,9 +372,102 @@ func (o *userAccountResourceType) Delete(ctx context.Context, resourceID *v2.Res
 	return nil, nil
 }
 
-func userAccountBuilder(client *jamf.Client) *userAccountResourceType {
+// passwordCharacterClasses are the character classes Jamf Pro's password
+// policy can require — upper- and lower-case letters, digits and special
+// characters. The policy is set per instance, so rotated passwords always
+// include each one.
+var passwordCharacterClasses = []string{
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"abcdefghijklmnopqrstuvwxyz",
+	"0123456789",
+	"!#$%&()*+,-.:;=?@[]^_{}~",
+}
+
+// withJamfPasswordPolicy returns credentialOptions with a constraint added
+// for every character class its random password options don't already
+// require. Without constraints the SDK includes every class by itself, and a
+// plaintext password is passed through for Jamf to validate.
+func withJamfPasswordPolicy(credentialOptions *v2.LocalCredentialOptions) *v2.LocalCredentialOptions {
+	randomPassword := credentialOptions.GetRandomPassword()
+	if randomPassword == nil || len(randomPassword.GetConstraints()) == 0 {
+		return credentialOptions
+	}
+
+	constraints := slices.Clone(randomPassword.GetConstraints())
+	for _, class := range passwordCharacterClasses {
+		required := slices.ContainsFunc(constraints, func(c *v2.PasswordConstraint) bool {
+			return c.GetMinCount() > 0 && c.GetCharSet() != "" && strings.Trim(c.GetCharSet(), class) == ""
+		})
+		if !required {
+			constraints = append(constraints, &v2.PasswordConstraint{CharSet: class, MinCount: 1})
+		}
+	}
+
+	return &v2.LocalCredentialOptions{
+		Options: &v2.LocalCredentialOptions_RandomPassword_{
+			RandomPassword: &v2.LocalCredentialOptions_RandomPassword{
+				Length:      randomPassword.GetLength(),
+				Constraints: constraints,
+			},
+		},
+	}
+}
+
+// RotateCapabilityDetails declares that C1 generates the new password, the
+// same way it does for CreateAccount.
+func (o *userAccountResourceType) RotateCapabilityDetails(_ context.Context) (*v2.CredentialDetailsCredentialRotation, annotations.Annotations, error) {
+	return &v2.CredentialDetailsCredentialRotation{
+		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
+		},
+		PreferredCredentialOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
+	}, nil, nil
+}
+
+// Rotate sets a new password on a local Jamf Pro console account and returns
+// it for the SDK to encrypt. Accounts backed by an LDAP server have no Jamf
+// password, so they're refused.
+func (o *userAccountResourceType) Rotate(
+	ctx context.Context,
+	resourceID *v2.ResourceId,
+	credentialOptions *v2.LocalCredentialOptions,
+) ([]*v2.PlaintextData, annotations.Annotations, error) {
+	id, err := strconv.Atoi(resourceID.Resource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: rotate userAccount: invalid resource id %q: %w", resourceID.Resource, err)
+	}
+
+	ctx = jamf.WithoutCache(ctx)
+	account, err := o.client.GetUserAccountDetails(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: rotate userAccount %d: %w", id, err)
+	}
+	if account.IsLDAP() {
+		return nil, nil, fmt.Errorf("jamf-connector: rotate userAccount %d: %s is backed by LDAP server %q and has no Jamf password", id, account.Name, account.LDAPServer.Name)
+	}
+
+	password, err := crypto.GeneratePassword(ctx, withJamfPasswordPolicy(credentialOptions))
+	if err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to generate random password: %w", err)
+	}
+
+	if err := o.client.UpdateUserAccount(ctx, id, jamf.UserAccountUpdateBody{Password: password}); err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: rotate userAccount %d: %w", id, err)
+	}
+
+	plaintextData := []*v2.PlaintextData{
+		{
+			Name:  "password",
+			Bytes: []byte(password),
+		},
+	}
+	return plaintextData, nil, nil
+}
+
+func userAccountBuilder(client *jamf.Client, disableOnDelete bool) *userAccountResourceType {
 	return &userAccountResourceType{
-		resourceType: resourceTypeUserAccount,
-		client:       client,
+		resourceType:    resourceTypeUserAccount,
+		client:          client,
+		disableOnDelete: disableOnDelete,
 	}
 }
@@ -0,0 +1,107 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	config "github.com/conductorone/baton-sdk/pb/c1/config/v1"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/actions"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// Names of the userAccount resource actions.
+	enableUserAccountAction  = "enable_user_account"
+	disableUserAccountAction = "disable_user_account"
+
+	// userAccountActionResourceArg is the user account an action applies to.
+	userAccountActionResourceArg = "resource_id"
+)
+
+// ResourceActions registers enabling and disabling a user account. A
+// disabled account can't sign in to Jamf Pro but keeps its privileges, group
+// memberships and audit history.
+func (o *userAccountResourceType) ResourceActions(ctx context.Context, registry actions.ActionRegistry) error {
+	accountArg := config.Field_builder{
+		Name:            userAccountActionResourceArg,
+		DisplayName:     "User Account",
+		Description:     "The Jamf Pro user account",
+		IsRequired:      true,
+		ResourceIdField: &config.ResourceIdField{},
+	}.Build()
+
+	for _, action := range []struct {
+		schema  *v2.BatonActionSchema
+		handler actions.ActionHandler
+	}{
+		{
+			schema: v2.BatonActionSchema_builder{
+				Name:        enableUserAccountAction,
+				DisplayName: "Enable User Account",
+				Description: "Enable the user account so it can sign in to Jamf Pro again",
+				Arguments:   []*config.Field{accountArg},
+				ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT_ENABLE},
+			}.Build(),
+			handler: o.enableUserAccount,
+		},
+		{
+			schema: v2.BatonActionSchema_builder{
+				Name:        disableUserAccountAction,
+				DisplayName: "Disable User Account",
+				Description: "Disable the user account so it can't sign in to Jamf Pro, keeping its audit history",
+				Arguments:   []*config.Field{accountArg},
+				ActionType:  []v2.ActionType{v2.ActionType_ACTION_TYPE_ACCOUNT_DISABLE},
+			}.Build(),
+			handler: o.disableUserAccount,
+		},
+	} {
+		if err := registry.Register(ctx, action.schema, action.handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *userAccountResourceType) enableUserAccount(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	return o.setUserAccountEnabled(ctx, args, enabledValue)
+}
+
+func (o *userAccountResourceType) disableUserAccount(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	return o.setUserAccountEnabled(ctx, args, disabledValue)
+}
+
+// setUserAccountEnabled PUTs the account's enabled value. Only that field is
+// sent, so the account's privileges and groups are left as they are.
+func (o *userAccountResourceType) setUserAccountEnabled(ctx context.Context, args *structpb.Struct, enabled string) (*structpb.Struct, annotations.Annotations, error) {
+	id, err := userAccountActionTarget(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := o.client.UpdateUserAccount(jamf.WithoutCache(ctx), id, jamf.UserAccountUpdateBody{Enabled: enabled}); err != nil {
+		return nil, nil, fmt.Errorf("jamf-connector: failed to set user account %d to %s: %w", id, enabled, err)
+	}
+
+	return actions.NewReturnValues(true), nil, nil
+}
+
+// userAccountActionTarget returns the ID of the user account named by the
+// action's resource_id argument.
+func userAccountActionTarget(args *structpb.Struct) (int, error) {
+	resourceId, err := actions.RequireResourceIDArg(args, userAccountActionResourceArg)
+	if err != nil {
+		return 0, fmt.Errorf("jamf-connector: %w", err)
+	}
+	if resourceId.GetResourceType() != resourceTypeUserAccount.Id {
+		return 0, fmt.Errorf("jamf-connector: user account actions need a %s resource, got %s", resourceTypeUserAccount.Id, resourceId.GetResourceType())
+	}
+	id, err := strconv.Atoi(resourceId.GetResource())
+	if err != nil {
+		return 0, fmt.Errorf("jamf-connector: invalid user account id %q: %w", resourceId.GetResource(), err)
+	}
+	return id, nil
+}
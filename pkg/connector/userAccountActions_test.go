@@ -0,0 +1,24 @@
+package connector
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestUserAccountActionTarget(t *testing.T) {
+	args := func(resourceType, resource string) *structpb.Struct {
+		return resourceIDArgs(t, userAccountActionResourceArg, resourceType, resource)
+	}
+
+	id, err := userAccountActionTarget(args(resourceTypeUserAccount.Id, "103"))
+	if err != nil || id != 103 {
+		t.Fatalf("expected user account 103, got %d (err %v)", id, err)
+	}
+	if _, err := userAccountActionTarget(args(resourceTypeUser.Id, "103")); err == nil {
+		t.Error("expected a user resource to be refused")
+	}
+	if _, err := userAccountActionTarget(args(resourceTypeUserAccount.Id, "admin3")); err == nil {
+		t.Error("expected a non-numeric id to be refused")
+	}
+}
@@ -1,7 +1,18 @@
 package connector
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/crypto"
 )
 
 func TestResolvePrivileges_CustomWithPrivileges(t *testing.T) {
@@ -63,3 +74,139 @@ func TestResolvePrivileges_DiscardsMalformedEntries(t *testing.T) {
 		t.Fatalf("expected malformed/empty entries filtered out, got %v", got.JSSObjects)
 	}
 }
+
+func TestWithJamfPasswordPolicy_AddsMissingClasses(t *testing.T) {
+	options := &v2.LocalCredentialOptions{
+		Options: &v2.LocalCredentialOptions_RandomPassword_{
+			RandomPassword: &v2.LocalCredentialOptions_RandomPassword{
+				Length:      16,
+				Constraints: []*v2.PasswordConstraint{{CharSet: "abcdefghijklmnopqrstuvwxyz", MinCount: 3}},
+			},
+		},
+	}
+	password, err := crypto.GeneratePassword(context.Background(), withJamfPasswordPolicy(options))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(password) != 16 {
+		t.Fatalf("expected a 16 character password, got %d", len(password))
+	}
+	for _, class := range passwordCharacterClasses {
+		if !strings.ContainsAny(password, class) {
+			t.Errorf("expected %q to contain one of %q", password, class)
+		}
+	}
+	if got := len(options.GetRandomPassword().GetConstraints()); got != 1 {
+		t.Errorf("expected the caller's options to be left alone, got %d constraints", got)
+	}
+}
+
+func TestWithJamfPasswordPolicy_KeepsOptionsWithoutConstraints(t *testing.T) {
+	options := &v2.LocalCredentialOptions{
+		Options: &v2.LocalCredentialOptions_RandomPassword_{
+			RandomPassword: &v2.LocalCredentialOptions_RandomPassword{Length: 12},
+		},
+	}
+	if got := withJamfPasswordPolicy(options); got != options {
+		t.Fatalf("expected options without constraints to be returned as is, got %v", got)
+	}
+}
+
+// TestUserAccountDelete checks that Delete removes the account, or only
+// disables it when the connector is configured to, and that an account
+// that's already gone counts as deleted.
+func TestUserAccountDelete(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		disableOnDelete bool
+		wantMethod      string
+		wantBody        string
+	}{
+		{name: "delete", wantMethod: http.MethodDelete},
+		{name: "disable", disableOnDelete: true, wantMethod: http.MethodPut, wantBody: "<enabled>Disabled</enabled>"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+				if r.URL.Path != "/JSSResource/accounts/userid/103" {
+					http.NotFound(w, r)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+			o := userAccountBuilder(newTestClient(t, handler), tt.disableOnDelete)
+
+			id := &v2.ResourceId{ResourceType: resourceTypeUserAccount.Id, Resource: "103"}
+			if _, err := o.Delete(context.Background(), id, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(requests) != 1 || !strings.HasPrefix(requests[0], tt.wantMethod+" /JSSResource/accounts/userid/103") || !strings.Contains(requests[0], tt.wantBody) {
+				t.Fatalf("expected a single %s of account 103 with %q, got %q", tt.wantMethod, tt.wantBody, requests)
+			}
+
+			missing := &v2.ResourceId{ResourceType: resourceTypeUserAccount.Id, Resource: "999"}
+			if _, err := o.Delete(context.Background(), missing, nil); err != nil {
+				t.Errorf("expected a missing account to count as deleted, got %v", err)
+			}
+		})
+	}
+}
+
+// TestUserAccountRotate rotates the password of a local account, which the
+// Classic API reports on ldap_server -1 ("None"), and refuses an LDAP one.
+func TestUserAccountRotate(t *testing.T) {
+	accounts := map[int]jamf.UserAccount{
+		101: {BaseType: jamf.BaseType{ID: 101, Name: "admin1"}, LDAPServer: jamf.BaseType{ID: -1, Name: "None"}},
+		104: {BaseType: jamf.BaseType{ID: 104, Name: "admin4"}, DirectoryUser: true, LDAPServer: jamf.BaseType{ID: 1, Name: "corp-ad"}},
+	}
+	passwords := map[int]string{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/JSSResource/accounts/userid/"))
+		account, ok := accounts[id]
+		if err != nil || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(jamf.UserAccountResponse{UserAccount: account})
+		case http.MethodPut:
+			var body jamf.UserAccountUpdateBody
+			if err := xml.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, "invalid body", http.StatusBadRequest)
+				return
+			}
+			passwords[id] = body.Password
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	o := userAccountBuilder(newTestClient(t, handler), false)
+	options := &v2.LocalCredentialOptions{
+		Options: &v2.LocalCredentialOptions_RandomPassword_{
+			RandomPassword: &v2.LocalCredentialOptions_RandomPassword{Length: 16},
+		},
+	}
+
+	plaintexts, _, err := o.Rotate(context.Background(), &v2.ResourceId{ResourceType: resourceTypeUserAccount.Id, Resource: "101"}, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plaintexts) != 1 || plaintexts[0].GetName() != "password" || len(plaintexts[0].GetBytes()) != 16 {
+		t.Fatalf("expected a 16 character password, got %v", plaintexts)
+	}
+	if passwords[101] != string(plaintexts[0].GetBytes()) {
+		t.Errorf("expected the returned password to be set on admin1, got %q", passwords[101])
+	}
+
+	if _, _, err := o.Rotate(context.Background(), &v2.ResourceId{ResourceType: resourceTypeUserAccount.Id, Resource: "104"}, options); err == nil {
+		t.Error("expected rotating an LDAP account to be refused")
+	}
+	if _, ok := passwords[104]; ok {
+		t.Error("expected no password to be set on an LDAP account")
+	}
+}
@@ -3,10 +3,12 @@ package connector
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 
 	"github.com/conductorone/baton-jamf/pkg/jamf"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
 	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
@@ -44,7 +46,13 @@ func userGroupResource(group *jamf.UserGroup, parentResourceID *v2.ResourceId) (
 }
 
 func (g *userGroupResourceType) List(ctx context.Context, parentId *v2.ResourceId, attrs rs.SyncOpAttrs) ([]*v2.Resource, *rs.SyncOpResults, error) {
-	userGroups, err := g.client.GetUserGroups(ctx)
+	ctx = jamf.WithSyncID(ctx, attrs.SyncID)
+	bag, offset, pageSize, err := parseOffsetPageToken(attrs.PageToken, g.resourceType.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userGroups, total, err := g.client.GetUserGroupsPage(ctx, offset, pageSize)
 	if err != nil {
 		return nil, nil, fmt.Errorf("jamf-connector: failed to list user groups: %w", err)
 	}
@@ -59,7 +67,12 @@ func (g *userGroupResourceType) List(ctx context.Context, parentId *v2.ResourceI
 		rv = append(rv, ur)
 	}
 
-	return rv, nil, nil
+	nextToken, err := nextOffsetPageToken(bag, offset+pageSize, total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rv, &rs.SyncOpResults{NextPageToken: nextToken}, nil
 }
 
 func (g *userGroupResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ rs.SyncOpAttrs) ([]*v2.Entitlement, *rs.SyncOpResults, error) {
@@ -104,6 +117,93 @@ func (g *userGroupResourceType) Grants(ctx context.Context, resource *v2.Resourc
 	return rv, nil, nil
 }
 
+// Grant adds a Jamf user to a static user group. Smart groups are refused:
+// their membership is computed by Jamf from the group's criteria, so a
+// direct addition would either be rejected or silently undone.
+func (g *userGroupResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		return nil, fmt.Errorf("jamf-connector: only users can be added to user groups, got %s", principal.Id.ResourceType)
+	}
+
+	userGroupId, userId, err := userGroupMembershipIDs(principal, entitlement)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := g.client.GetUserGroupDetails(ctx, userGroupId)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: grant user group membership: failed to get user group %d: %w", userGroupId, err)
+	}
+	if group.IsSmart {
+		return nil, fmt.Errorf("jamf-connector: user group %q is a smart group; its membership is computed by Jamf and can't be granted", group.Name)
+	}
+	if hasUserGroupMember(group, userId) {
+		return annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	err = g.client.AddUserGroupMember(ctx, userGroupId, userId)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to add user %d to user group %d: %w", userId, userGroupId, err)
+	}
+
+	return nil, nil
+}
+
+// Revoke removes a Jamf user from a static user group. Smart groups are
+// refused for the same reason as in Grant.
+func (g *userGroupResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	ctx = jamf.WithoutCache(ctx)
+	principal := grant.Principal
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		return nil, fmt.Errorf("jamf-connector: only users can be removed from user groups, got %s", principal.Id.ResourceType)
+	}
+
+	userGroupId, userId, err := userGroupMembershipIDs(principal, grant.Entitlement)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := g.client.GetUserGroupDetails(ctx, userGroupId)
+	if err != nil {
+		if jamf.IsNotFoundError(err) {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		return nil, fmt.Errorf("jamf-connector: revoke user group membership: failed to get user group %d: %w", userGroupId, err)
+	}
+	if group.IsSmart {
+		return nil, fmt.Errorf("jamf-connector: user group %q is a smart group; its membership is computed by Jamf and can't be revoked", group.Name)
+	}
+	if !hasUserGroupMember(group, userId) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	err = g.client.RemoveUserGroupMember(ctx, userGroupId, userId)
+	if err != nil {
+		return nil, fmt.Errorf("jamf-connector: failed to remove user %d from user group %d: %w", userId, userGroupId, err)
+	}
+
+	return nil, nil
+}
+
+// userGroupMembershipIDs parses the user group ID out of entitlement and the
+// user ID out of principal.
+func userGroupMembershipIDs(principal *v2.Resource, entitlement *v2.Entitlement) (int, int, error) {
+	userGroupId, err := strconv.Atoi(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return 0, 0, fmt.Errorf("jamf-connector: invalid user group id %q: %w", entitlement.Resource.Id.Resource, err)
+	}
+	userId, err := strconv.Atoi(principal.Id.Resource)
+	if err != nil {
+		return 0, 0, fmt.Errorf("jamf-connector: invalid user id %q: %w", principal.Id.Resource, err)
+	}
+	return userGroupId, userId, nil
+}
+
+func hasUserGroupMember(group *jamf.UserGroup, userId int) bool {
+	return slices.ContainsFunc(group.Users, func(u jamf.User) bool { return u.ID == userId })
+}
+
 func userGroupBuilder(client *jamf.Client) *userGroupResourceType {
 	return &userGroupResourceType{
 		resourceType: resourceTypeUserGroup,
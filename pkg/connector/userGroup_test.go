@@ -0,0 +1,130 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+// fakeUserGroups serves GET and PUT on /JSSResource/usergroups/id/{id} from
+// the members of each user group, and counts the PUTs.
+type fakeUserGroups struct {
+	mu      sync.Mutex
+	members map[int][]int
+	smart   map[int]bool
+	puts    int
+}
+
+func (f *fakeUserGroups) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/JSSResource/usergroups/id/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	userIds, ok := f.members[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		group := jamf.UserGroup{BaseType: jamf.BaseType{ID: id, Name: "usergroup-" + strconv.Itoa(id)}, IsSmart: f.smart[id]}
+		for _, userId := range userIds {
+			group.Users = append(group.Users, jamf.User{BaseType: jamf.BaseType{ID: userId}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jamf.UserGroupResponse{UserGroup: group})
+	case http.MethodPut:
+		var body jamf.UserGroupUpdateBody
+		if err := xml.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+		if body.UserAdditions != nil {
+			for _, user := range body.UserAdditions.Users {
+				f.members[id] = append(f.members[id], user.ID)
+			}
+		}
+		if body.UserDeletions != nil {
+			for _, user := range body.UserDeletions.Users {
+				f.members[id] = slices.DeleteFunc(f.members[id], func(userId int) bool { return userId == user.ID })
+			}
+		}
+		f.puts++
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestUserGroupMembershipGrantAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	userGroups := &fakeUserGroups{
+		members: map[int][]int{301: {1}, 302: {}},
+		smart:   map[int]bool{302: true},
+	}
+	g := userGroupBuilder(newTestClient(t, userGroups))
+
+	user := &v2.Resource{Id: &v2.ResourceId{ResourceType: resourceTypeUser.Id, Resource: "2"}}
+	userGroup := func(id string) *v2.Entitlement {
+		return &v2.Entitlement{Resource: &v2.Resource{Id: &v2.ResourceId{ResourceType: resourceTypeUserGroup.Id, Resource: id}}}
+	}
+	grant := &v2.Grant{Principal: user, Entitlement: userGroup("301")}
+
+	annos, err := g.Grant(ctx, user, userGroup("301"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hasAnnotation(annos, &v2.GrantAlreadyExists{}) || !slices.Equal(userGroups.members[301], []int{1, 2}) {
+		t.Fatalf("expected the grant to add user 2, got %v", userGroups.members[301])
+	}
+
+	annos, err = g.Grant(ctx, user, userGroup("301"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasAnnotation(annos, &v2.GrantAlreadyExists{}) || userGroups.puts != 1 {
+		t.Fatalf("expected the second grant to be reported as already existing without an update, got %d updates", userGroups.puts)
+	}
+
+	annos, err = g.Revoke(ctx, grant)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) || !slices.Equal(userGroups.members[301], []int{1}) {
+		t.Fatalf("expected the revoke to remove only user 2, got %v", userGroups.members[301])
+	}
+
+	annos, err = g.Revoke(ctx, grant)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) || userGroups.puts != 2 {
+		t.Fatalf("expected the second revoke to be reported as already revoked without an update, got %d updates", userGroups.puts)
+	}
+
+	annos, err = g.Revoke(ctx, &v2.Grant{Principal: user, Entitlement: userGroup("399")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasAnnotation(annos, &v2.GrantAlreadyRevoked{}) {
+		t.Error("expected revoking from a missing user group to be reported as already revoked")
+	}
+
+	if _, err := g.Grant(ctx, user, userGroup("302")); err == nil {
+		t.Error("expected a grant on a smart group to be refused")
+	}
+}
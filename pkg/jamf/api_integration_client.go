@@ -0,0 +1,64 @@
+package jamf
+
+import (
+	"context"
+	liburl "net/url"
+	"strconv"
+)
+
+const (
+	apiRolesUrlPath        = "/api/v1/api-roles"
+	apiIntegrationsUrlPath = "/api/v1/api-integrations"
+
+	// apiPageSize is the page size used when reading every page of a Jamf
+	// Pro API list.
+	apiPageSize = 100
+)
+
+// GetAPIRoles returns all Jamf Pro API roles, memoized for the sync in ctx
+// (see WithSyncID). Requires the "Read API Roles" privilege.
+func (c *Client) GetAPIRoles(ctx context.Context) ([]APIRole, error) {
+	return c.cache.apiRoles.get(ctx, func(ctx context.Context) ([]APIRole, error) {
+		return getAllPages[APIRole](ctx, c, apiRolesUrlPath, nil)
+	})
+}
+
+// GetAPIIntegrations returns all Jamf Pro API integrations, memoized for the
+// sync in ctx (see WithSyncID). Requires the "Read API Integrations"
+// privilege.
+func (c *Client) GetAPIIntegrations(ctx context.Context) ([]APIIntegration, error) {
+	return c.cache.apiIntegrations.get(ctx, func(ctx context.Context) ([]APIIntegration, error) {
+		return getAllPages[APIIntegration](ctx, c, apiIntegrationsUrlPath, nil)
+	})
+}
+
+// getAllPages reads every page of a zero-indexed Jamf Pro API list, sending
+// extra along with the paging parameters. It stops at totalCount or at the
+// first empty page, whichever comes first, so a count that shrinks mid-read
+// can't keep it going.
+func getAllPages[T any](ctx context.Context, c *Client, path string, extra liburl.Values) ([]T, error) {
+	var all []T
+	for page := 0; ; page++ {
+		url, err := c.getUrl(path)
+		if err != nil {
+			return nil, err
+		}
+		query := liburl.Values{}
+		for key, values := range extra {
+			query[key] = values
+		}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("page-size", strconv.Itoa(apiPageSize))
+		url.RawQuery = query.Encode()
+
+		var target pagedResponse[T]
+		if err := c.doRequest(ctx, url, &target); err != nil {
+			return nil, err
+		}
+
+		all = append(all, target.Results...)
+		if len(target.Results) == 0 || len(all) >= target.TotalCount {
+			return all, nil
+		}
+	}
+}
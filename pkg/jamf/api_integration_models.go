@@ -0,0 +1,27 @@
+package jamf
+
+// pagedResponse is the envelope the Jamf Pro API wraps list results in.
+type pagedResponse[T any] struct {
+	TotalCount int `json:"totalCount"`
+	Results    []T `json:"results"`
+}
+
+// APIRole is a Jamf Pro API role: a named set of privileges that API
+// integrations are authorized with.
+type APIRole struct {
+	ID          string   `json:"id"`
+	DisplayName string   `json:"displayName"`
+	Privileges  []string `json:"privileges"`
+}
+
+// APIIntegration is a Jamf Pro API integration (an API client). Its
+// AuthorizationScopes are the display names of the API roles it holds.
+type APIIntegration struct {
+	ID                         int      `json:"id"`
+	DisplayName                string   `json:"displayName"`
+	Enabled                    bool     `json:"enabled"`
+	AuthorizationScopes        []string `json:"authorizationScopes"`
+	AccessTokenLifetimeSeconds int      `json:"accessTokenLifetimeSeconds"`
+	AppType                    string   `json:"appType"`
+	ClientID                   string   `json:"clientId"`
+}
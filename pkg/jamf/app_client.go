@@ -0,0 +1,142 @@
+package jamf
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	macApplicationUrlPath  = "/JSSResource/macapplications/id/%d"
+	macApplicationsUrlPath = "/JSSResource/macapplications"
+
+	mobileDeviceApplicationUrlPath  = "/JSSResource/mobiledeviceapplications/id/%d"
+	mobileDeviceApplicationsUrlPath = "/JSSResource/mobiledeviceapplications"
+
+	vppAssignmentUrlPath  = "/JSSResource/vppassignments/id/%d"
+	vppAssignmentsUrlPath = "/JSSResource/vppassignments"
+)
+
+// GetMacApplicationDetails returns the Mac App Store app with the given ID,
+// including its scope. Requires the "Read Mac Applications" privilege.
+func (c *Client) GetMacApplicationDetails(ctx context.Context, appId int) (*MacApplication, error) {
+	url, err := c.getUrl(fmt.Sprintf(macApplicationUrlPath, appId))
+	if err != nil {
+		return nil, err
+	}
+
+	var target MacApplicationResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return &target.MacApplication, nil
+}
+
+// GetMacApplicationsPage returns the details of up to limit Mac App Store
+// apps starting at offset, and the total number of them. See GetUsersPage.
+func (c *Client) GetMacApplicationsPage(ctx context.Context, offset, limit int) ([]*MacApplication, int, error) {
+	ids, err := c.cache.macApplicationIDs.get(ctx, c.getMacApplicationIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	apps, err := fetchDetails(ctx, c.concurrency, pageOf(ids, offset, limit), c.GetMacApplicationDetails)
+	if err != nil {
+		return nil, 0, err
+	}
+	return apps, len(ids), nil
+}
+
+func (c *Client) getMacApplicationIDs(ctx context.Context) ([]int, error) {
+	url, err := c.getUrl(macApplicationsUrlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var target MacApplicationsResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return sortedIDs(target.MacApplications), nil
+}
+
+// GetMobileDeviceApplicationDetails returns the mobile device app with the
+// given ID, including its scope. Requires the "Read Mobile Device Apps"
+// privilege.
+func (c *Client) GetMobileDeviceApplicationDetails(ctx context.Context, appId int) (*MobileDeviceApplication, error) {
+	url, err := c.getUrl(fmt.Sprintf(mobileDeviceApplicationUrlPath, appId))
+	if err != nil {
+		return nil, err
+	}
+
+	var target MobileDeviceApplicationResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return &target.MobileDeviceApplication, nil
+}
+
+// GetMobileDeviceApplicationsPage returns the details of up to limit mobile
+// device apps starting at offset, and the total number of them. See
+// GetUsersPage.
+func (c *Client) GetMobileDeviceApplicationsPage(ctx context.Context, offset, limit int) ([]*MobileDeviceApplication, int, error) {
+	ids, err := c.cache.mobileDeviceApplicationIDs.get(ctx, c.getMobileDeviceApplicationIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	apps, err := fetchDetails(ctx, c.concurrency, pageOf(ids, offset, limit), c.GetMobileDeviceApplicationDetails)
+	if err != nil {
+		return nil, 0, err
+	}
+	return apps, len(ids), nil
+}
+
+func (c *Client) getMobileDeviceApplicationIDs(ctx context.Context) ([]int, error) {
+	url, err := c.getUrl(mobileDeviceApplicationsUrlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var target MobileDeviceApplicationsResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return sortedIDs(target.MobileDeviceApplications), nil
+}
+
+// GetVPPAssignmentDetails returns the VPP assignment with the given ID.
+// Requires the "Read VPP Assignments" privilege.
+func (c *Client) GetVPPAssignmentDetails(ctx context.Context, assignmentId int) (*VPPAssignment, error) {
+	url, err := c.getUrl(fmt.Sprintf(vppAssignmentUrlPath, assignmentId))
+	if err != nil {
+		return nil, err
+	}
+
+	var target VPPAssignmentResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return &target.VPPAssignment, nil
+}
+
+// GetVPPAssignments returns every VPP assignment with its apps and scope,
+// memoized for the sync in ctx (see WithSyncID).
+func (c *Client) GetVPPAssignments(ctx context.Context) ([]*VPPAssignment, error) {
+	return c.cache.vppAssignments.get(ctx, func(ctx context.Context) ([]*VPPAssignment, error) {
+		url, err := c.getUrl(vppAssignmentsUrlPath)
+		if err != nil {
+			return nil, err
+		}
+
+		var target VPPAssignmentsResponse
+		if err := c.doRequest(ctx, url, &target); err != nil {
+			return nil, err
+		}
+		return fetchDetails(ctx, c.concurrency, sortedIDs(target.VPPAssignments), c.GetVPPAssignmentDetails)
+	})
+}
@@ -0,0 +1,176 @@
+package jamf
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// UserScopeTargets are the Jamf users and user groups a scope (or its
+// exclusions) lists. Apps and VPP assignments can be scoped to users as well
+// as devices.
+type UserScopeTargets struct {
+	JSSUsers      []BaseType `json:"jss_users"`
+	JSSUserGroups []BaseType `json:"jss_user_groups"`
+}
+
+// MacApplicationScope is the <scope> block of a Mac App Store app: a
+// ComputerScope that can also target users.
+type MacApplicationScope struct {
+	AllComputers bool `json:"all_computers"`
+	AllJSSUsers  bool `json:"all_jss_users"`
+	ComputerScopeTargets
+	UserScopeTargets
+	Exclusions MacApplicationScopeExclusions `json:"exclusions"`
+}
+
+type MacApplicationScopeExclusions struct {
+	ComputerScopeTargets
+	UserScopeTargets
+}
+
+// Computers returns the computer half of the scope.
+func (s *MacApplicationScope) Computers() ComputerScope {
+	return ComputerScope{
+		AllComputers:         s.AllComputers,
+		ComputerScopeTargets: s.ComputerScopeTargets,
+		Exclusions:           s.Exclusions.ComputerScopeTargets,
+	}
+}
+
+// MobileDeviceApplicationScope is the <scope> block of a mobile device app:
+// a MobileDeviceScope that can also target users.
+type MobileDeviceApplicationScope struct {
+	AllMobileDevices bool `json:"all_mobile_devices"`
+	AllJSSUsers      bool `json:"all_jss_users"`
+	MobileDeviceScopeTargets
+	UserScopeTargets
+	Exclusions MobileDeviceApplicationScopeExclusions `json:"exclusions"`
+}
+
+type MobileDeviceApplicationScopeExclusions struct {
+	MobileDeviceScopeTargets
+	UserScopeTargets
+}
+
+// MobileDevices returns the mobile device half of the scope.
+func (s *MobileDeviceApplicationScope) MobileDevices() MobileDeviceScope {
+	return MobileDeviceScope{
+		AllMobileDevices:         s.AllMobileDevices,
+		MobileDeviceScopeTargets: s.MobileDeviceScopeTargets,
+		Exclusions:               s.Exclusions.MobileDeviceScopeTargets,
+	}
+}
+
+// AppVPP is the <vpp> block of an app distributed with Volume Purchasing
+// licenses.
+type AppVPP struct {
+	AssignVPPDeviceBasedLicenses bool `json:"assign_vpp_device_based_licenses"`
+	VPPAdminAccountID            int  `json:"vpp_admin_account_id"`
+}
+
+// MacApplication is a Mac App Store app distributed through Jamf.
+type MacApplication struct {
+	General MacApplicationGeneral `json:"general"`
+	Scope   MacApplicationScope   `json:"scope"`
+	VPP     AppVPP                `json:"vpp"`
+}
+
+type MacApplicationGeneral struct {
+	BaseType
+	Version        string   `json:"version"`
+	BundleID       string   `json:"bundle_id"`
+	URL            string   `json:"url"`
+	IsFree         bool     `json:"is_free"`
+	DeploymentType string   `json:"deployment_type"`
+	Category       BaseType `json:"category"`
+	Site           BaseType `json:"site"`
+}
+
+// MobileDeviceApplication is an App Store or in-house app distributed to
+// mobile devices through Jamf.
+type MobileDeviceApplication struct {
+	General MobileDeviceApplicationGeneral `json:"general"`
+	Scope   MobileDeviceApplicationScope   `json:"scope"`
+	VPP     AppVPP                         `json:"vpp"`
+}
+
+type MobileDeviceApplicationGeneral struct {
+	BaseType
+	DisplayName    string   `json:"display_name"`
+	Version        string   `json:"version"`
+	BundleID       string   `json:"bundle_id"`
+	ITunesStoreURL string   `json:"itunes_store_url"`
+	Free           bool     `json:"free"`
+	DeploymentType string   `json:"deployment_type"`
+	Category       BaseType `json:"category"`
+	Site           BaseType `json:"site"`
+}
+
+// VPPAssignment assigns Volume Purchasing licenses for a set of apps to the
+// users in its scope.
+type VPPAssignment struct {
+	General VPPAssignmentGeneral `json:"general"`
+	IOSApps []VPPApp             `json:"ios_apps"`
+	MacApps []VPPApp             `json:"mac_apps"`
+	Scope   VPPAssignmentScope   `json:"scope"`
+}
+
+type VPPAssignmentGeneral struct {
+	BaseType
+	VPPAdminAccountID   int    `json:"vpp_admin_account_id"`
+	VPPAdminAccountName string `json:"vpp_admin_account_name"`
+}
+
+// VPPApp is an app in a VPP assignment, identified by its App Store ID.
+type VPPApp struct {
+	AdamID int    `json:"adam_id"`
+	Name   string `json:"name"`
+}
+
+type VPPAssignmentScope struct {
+	AllJSSUsers bool `json:"all_jss_users"`
+	UserScopeTargets
+	Exclusions UserScopeTargets `json:"exclusions"`
+}
+
+type MacApplicationsResponse struct {
+	MacApplications []BaseType `json:"mac_applications"`
+}
+
+type MacApplicationResponse struct {
+	MacApplication MacApplication `json:"mac_application"`
+}
+
+type MobileDeviceApplicationsResponse struct {
+	MobileDeviceApplications []BaseType `json:"mobile_device_applications"`
+}
+
+type MobileDeviceApplicationResponse struct {
+	MobileDeviceApplication MobileDeviceApplication `json:"mobile_device_application"`
+}
+
+type VPPAssignmentsResponse struct {
+	VPPAssignments []BaseType `json:"vpp_assignments"`
+}
+
+type VPPAssignmentResponse struct {
+	VPPAssignment VPPAssignment `json:"vpp_assignment"`
+}
+
+// appStoreIDPattern matches the App Store ID in an App Store URL, such as
+// https://apps.apple.com/us/app/slack/id618783545.
+var appStoreIDPattern = regexp.MustCompile(`/id(\d+)`)
+
+// AppStoreID returns the App Store (Adam) ID in an App Store URL, or 0 if
+// there isn't one, as for an in-house app.
+func AppStoreID(url string) int {
+	match := appStoreIDPattern.FindStringSubmatch(url)
+	if match == nil {
+		return 0
+	}
+	id, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,109 @@
+package jamf
+
+import (
+	"context"
+	"sync"
+)
+
+type syncIDKey struct{}
+
+// WithSyncID returns a context whose listings (GetUsers, GetUserGroups,
+// GetAccounts, GetSites, GetAPIRoles, GetComputerGroups, ...) are memoized for
+// the sync with the given ID. Resource syncers wrap their context with it so
+// the many syncers that need the same listing (sites, roles and groups all
+// read every account) fetch it once per sync instead of once per resource.
+// An empty syncID disables memoization.
+func WithSyncID(ctx context.Context, syncID string) context.Context {
+	return context.WithValue(ctx, syncIDKey{}, syncID)
+}
+
+// memoizedSyncID returns the sync a read may be answered from memo for, or ""
+// if it must go to Jamf.
+func memoizedSyncID(ctx context.Context) string {
+	if uncached, _ := ctx.Value(uncachedKey{}).(bool); uncached {
+		return ""
+	}
+	syncID, _ := ctx.Value(syncIDKey{}).(string)
+	return syncID
+}
+
+// memo holds one listing for the life of a sync. Callers that miss at the
+// same time wait for a single load rather than each fetching their own, and
+// a result from an earlier sync is never served to a later one.
+type memo[T any] struct {
+	mu     sync.Mutex
+	syncID string
+	value  T
+}
+
+// get returns the value loaded for the context's sync, loading it first if
+// there isn't one. Errors aren't memoized, so the next call retries.
+func (m *memo[T]) get(ctx context.Context, load func(ctx context.Context) (T, error)) (T, error) {
+	syncID := memoizedSyncID(ctx)
+	if syncID == "" {
+		return load(ctx)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.syncID == syncID {
+		return m.value, nil
+	}
+
+	value, err := load(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	m.syncID = syncID
+	m.value = value
+	return value, nil
+}
+
+// invalidate drops the memoized value. A load in progress finishes first, so
+// its result can't outlive the write that prompted the invalidation.
+func (m *memo[T]) invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var zero T
+	m.syncID = ""
+	m.value = zero
+}
+
+// accounts is the memoized result of GetAccounts.
+type accounts struct {
+	userAccounts []*UserAccount
+	groups       []*Group
+}
+
+// syncCache memoizes the listings every sync reads from several syncers.
+// Results are shared between callers and must not be modified.
+type syncCache struct {
+	users      memo[[]*User]
+	userGroups memo[[]*UserGroup]
+	accounts   memo[accounts]
+	sites      memo[*[]Site]
+
+	apiRoles        memo[[]APIRole]
+	apiIntegrations memo[[]APIIntegration]
+
+	computerGroups        memo[[]*ComputerGroup]
+	computerLocations     memo[[]ComputerInventory]
+	mobileDeviceGroups    memo[[]*MobileDeviceGroup]
+	mobileDeviceLocations memo[[]MobileDeviceDetail]
+	vppAssignments        memo[[]*VPPAssignment]
+
+	// The ID lists the paged getters slice, sorted.
+	userIDs                             memo[[]int]
+	userGroupIDs                        memo[[]int]
+	groupIDs                            memo[[]int]
+	computerGroupIDs                    memo[[]int]
+	mobileDeviceGroupIDs                memo[[]int]
+	policyIDs                           memo[[]int]
+	macOSConfigurationProfileIDs        memo[[]int]
+	mobileDeviceConfigurationProfileIDs memo[[]int]
+	macApplicationIDs                   memo[[]int]
+	mobileDeviceApplicationIDs          memo[[]int]
+}
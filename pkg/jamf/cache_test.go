@@ -0,0 +1,221 @@
+package jamf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func countingLoad(loads *atomic.Int32) func(ctx context.Context) (int, error) {
+	return func(ctx context.Context) (int, error) {
+		return int(loads.Add(1)), nil
+	}
+}
+
+func TestMemo_LoadsOncePerSync(t *testing.T) {
+	var (
+		m     memo[int]
+		loads atomic.Int32
+	)
+	load := countingLoad(&loads)
+	first := WithSyncID(context.Background(), "sync-1")
+
+	for range 3 {
+		if v, err := m.get(first, load); err != nil || v != 1 {
+			t.Fatalf("expected memoized value 1, got %d (err %v)", v, err)
+		}
+	}
+
+	second := WithSyncID(context.Background(), "sync-2")
+	if v, err := m.get(second, load); err != nil || v != 2 {
+		t.Fatalf("expected a new sync to reload, got %d (err %v)", v, err)
+	}
+	if got := loads.Load(); got != 2 {
+		t.Errorf("expected 2 loads, got %d", got)
+	}
+}
+
+func TestMemo_BypassedWithoutSyncID(t *testing.T) {
+	var (
+		m     memo[int]
+		loads atomic.Int32
+	)
+	load := countingLoad(&loads)
+
+	for _, ctx := range []context.Context{
+		context.Background(),
+		WithSyncID(context.Background(), ""),
+		WithoutCache(WithSyncID(context.Background(), "sync-1")),
+		WithoutCache(WithSyncID(context.Background(), "sync-1")),
+	} {
+		if _, err := m.get(ctx, load); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := loads.Load(); got != 4 {
+		t.Errorf("expected every call to load, got %d loads", got)
+	}
+}
+
+func TestMemo_InvalidateForcesReload(t *testing.T) {
+	var (
+		m     memo[int]
+		loads atomic.Int32
+	)
+	load := countingLoad(&loads)
+	ctx := WithSyncID(context.Background(), "sync-1")
+
+	if _, err := m.get(ctx, load); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m.invalidate()
+	if v, err := m.get(ctx, load); err != nil || v != 2 {
+		t.Fatalf("expected a reload after invalidate, got %d (err %v)", v, err)
+	}
+}
+
+func TestMemo_ErrorsAreNotMemoized(t *testing.T) {
+	var m memo[int]
+	ctx := WithSyncID(context.Background(), "sync-1")
+
+	_, err := m.get(ctx, func(ctx context.Context) (int, error) {
+		return 0, errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	v, err := m.get(ctx, func(ctx context.Context) (int, error) {
+		return 7, nil
+	})
+	if err != nil || v != 7 {
+		t.Fatalf("expected a retry after a failed load, got %d (err %v)", v, err)
+	}
+}
+
+func TestMemo_ConcurrentMissesShareOneLoad(t *testing.T) {
+	var (
+		m     memo[int]
+		loads atomic.Int32
+		wg    sync.WaitGroup
+	)
+	load := countingLoad(&loads)
+	ctx := WithSyncID(context.Background(), "sync-1")
+
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.get(ctx, load); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := loads.Load(); got != 1 {
+		t.Errorf("expected 1 load, got %d", got)
+	}
+}
+
+// TestClient_WritesInvalidateMemoizedListings checks that provisioning a
+// user drops the memoized user and user group listings, so a later read in
+// the same sync sees the change.
+func TestClient_WritesInvalidateMemoizedListings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, Credentials{Username: "user", Password: "pass"})
+	client.tokens.token = newBearerToken("token", time.Now(), 0)
+
+	ctx := WithSyncID(context.Background(), "sync-1")
+	client.cache.users.syncID, client.cache.users.value = "sync-1", []*User{{}}
+	client.cache.userGroups.syncID = "sync-1"
+	client.cache.accounts.syncID = "sync-1"
+
+	if err := client.DeleteUser(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if client.cache.users.syncID != "" || client.cache.users.value != nil {
+		t.Error("expected DeleteUser to invalidate the user listing")
+	}
+	if client.cache.userGroups.syncID != "" {
+		t.Error("expected DeleteUser to invalidate the user group listing")
+	}
+	if client.cache.accounts.syncID != "sync-1" {
+		t.Error("expected DeleteUser to leave the account listing alone")
+	}
+}
+
+// TestClient_PagesFetchOnlyTheirOwnDetails checks that a page of users
+// fetches the details of the users on it and no others, in ID order, so the
+// work per page stays bounded and a resumed sync doesn't redo earlier pages.
+func TestClient_PagesFetchOnlyTheirOwnDetails(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		fetched []int
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc(usersUrlPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(UsersResponse{Users: []BaseType{{ID: 3}, {ID: 1}, {ID: 2}}})
+	})
+	mux.HandleFunc("/JSSResource/users/id/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(r.PathValue("id"))
+		mu.Lock()
+		fetched = append(fetched, id)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(UserResponse{User: User{BaseType: BaseType{ID: id}}})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, Credentials{Username: "user", Password: "pass"})
+	client.tokens.token = newBearerToken("token", time.Now(), 0)
+	ctx := WithSyncID(context.Background(), "sync-1")
+
+	for _, tt := range []struct {
+		offset int
+		want   []int
+	}{
+		{offset: 0, want: []int{1, 2}},
+		{offset: 2, want: []int{3}},
+	} {
+		mu.Lock()
+		fetched = nil
+		mu.Unlock()
+
+		users, total, err := client.GetUsersPage(ctx, tt.offset, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if total != 3 {
+			t.Fatalf("expected a total of 3 users, got %d", total)
+		}
+		var got []int
+		for _, user := range users {
+			got = append(got, user.ID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("expected the page at %d to list users %v, got %v", tt.offset, tt.want, got)
+		}
+
+		mu.Lock()
+		slices.Sort(fetched)
+		if !slices.Equal(fetched, tt.want) {
+			t.Errorf("expected the page at %d to fetch the details of users %v, got %v", tt.offset, tt.want, fetched)
+		}
+		mu.Unlock()
+	}
+}
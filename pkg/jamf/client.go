@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	liburl "net/url"
+	"slices"
 	"time"
 
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
@@ -24,10 +25,12 @@ const (
 	groupUrlPath           = "/JSSResource/accounts/groupid/%d"
 	sitesUrlPath           = "/JSSResource/sites"
 	tokenUrlPath           = "/api/v1/auth/token" //nolint:golint,gosec // not a token
+	oauthTokenUrlPath      = "/api/oauth/token"   //nolint:golint,gosec // not a token
 	userGroupUrlPath       = "/JSSResource/usergroups/id/%d"
 	userGroupsUrlPath      = "/JSSResource/usergroups"
 	userUrlPath            = "/JSSResource/users/id/%d"
 	userNameUrlPath        = "/JSSResource/users/name/%s"
+	userEmailUrlPath       = "/JSSResource/users/email/%s"
 	usersUrlPath           = "/JSSResource/users"
 	keepAliveUrlPath       = "/api/v1/auth/keep-alive"
 	privilegesUrlPath      = "/api/v1/api-role-privileges"
@@ -38,36 +41,110 @@ const (
 	newResourceID = 0
 )
 
+// Credentials authenticate a Client. Set either Username and Password (a
+// Jamf Pro user account, via basic auth against /api/v1/auth/token) or
+// ClientID and ClientSecret (an API client, via the OAuth client_credentials
+// grant against /api/oauth/token).
+type Credentials struct {
+	Username string
+	Password string
+
+	ClientID     string
+	ClientSecret string
+}
+
+// isAPIClient reports whether the credentials are for an API client.
+func (c Credentials) isAPIClient() bool {
+	return c.ClientID != ""
+}
+
 type Client struct {
-	wrapper       *uhttp.BaseHttpClient
-	token         string
-	instanceURL   string
-	lastKeepAlive time.Time
+	wrapper     *uhttp.BaseHttpClient
+	instanceURL string
+
+	credentials Credentials
+	tokens      *tokenSource
 
-	userName string
-	password string
+	// concurrency bounds the detail requests GetUsers, GetUserGroups and
+	// GetAccounts run at once.
+	concurrency int
+
+	// retryPolicy governs retries of rate-limited and transiently failing
+	// requests.
+	retryPolicy RetryPolicy
+
+	// cache memoizes listings for contexts carrying a sync ID (see
+	// WithSyncID). Methods that write users, user groups or accounts
+	// invalidate the listing they change.
+	cache syncCache
 }
 
 func NewClient(
 	wrapper *uhttp.BaseHttpClient,
-	userName string,
-	password string,
-	token string,
+	credentials Credentials,
 	instanceURL string,
 ) *Client {
-	return &Client{
-		wrapper:       wrapper,
-		token:         token,
-		instanceURL:   instanceURL,
-		lastKeepAlive: time.Now(),
-		userName:      userName,
-		password:      password,
+	c := &Client{
+		wrapper:     wrapper,
+		instanceURL: instanceURL,
+		credentials: credentials,
+		concurrency: DefaultConcurrency,
+		retryPolicy: DefaultRetryPolicy(),
+	}
+
+	c.tokens = &tokenSource{acquire: c.acquireToken}
+	// The keep-alive endpoint only extends user tokens; an API client has to
+	// go through the client_credentials grant again.
+	if !credentials.isAPIClient() {
+		c.tokens.extend = c.keepAliveToken
+	}
+
+	return c
+}
+
+// Authenticate makes sure the client holds a usable bearer token, acquiring
+// one with its credentials if needed. Requests authenticate on their own;
+// this lets callers surface bad credentials up front.
+func (c *Client) Authenticate(ctx context.Context) error {
+	_, err := c.tokens.Token(ctx)
+	return err
+}
+
+// SetConcurrency sets how many detail requests the client runs at once when
+// listing users, user groups and accounts. Values below 1 are treated as 1.
+func (c *Client) SetConcurrency(concurrency int) {
+	c.concurrency = max(concurrency, 1)
+}
+
+// SetRetryPolicy sets how rate-limited and transiently failing requests are
+// retried. MaxAttempts below 1 is treated as 1 (no retries).
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	policy.MaxAttempts = max(policy.MaxAttempts, 1)
+	c.retryPolicy = policy
+}
+
+// UsesAPIClient reports whether the client authenticates as an API client
+// rather than a user account.
+func (c *Client) UsesAPIClient() bool {
+	return c.credentials.isAPIClient()
+}
+
+// acquireToken gets a new bearer token with the client's credentials.
+func (c *Client) acquireToken(ctx context.Context) (bearerToken, error) {
+	if c.credentials.isAPIClient() {
+		return c.createOAuthToken(ctx, c.credentials.ClientID, c.credentials.ClientSecret)
 	}
+	return c.createBearerToken(ctx, c.credentials.Username, c.credentials.Password)
 }
 
-func (c *Client) SetBearerToken(token string) {
-	c.lastKeepAlive = time.Now()
-	c.token = token
+type uncachedKey struct{}
+
+// WithoutCache returns a context whose reads bypass the HTTP response cache.
+// Provisioning paths use it so a grant or revoke decides — and verifies —
+// against Jamf's current state rather than a response cached earlier in the
+// sync.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, uncachedKey{}, true)
 }
 
 func (c *Client) getUrl(path string) (*liburl.URL, error) {
@@ -78,24 +155,16 @@ func (c *Client) getUrl(path string) (*liburl.URL, error) {
 	return liburl.Parse(urlString)
 }
 
-func (c *Client) keepAliveToken(
-	ctx context.Context,
-) error {
+// keepAliveToken trades a still-valid user token for a new one with a later
+// expiry.
+func (c *Client) keepAliveToken(ctx context.Context, token string) (bearerToken, error) {
 	l := ctxzap.Extract(ctx)
 
-	if c.token == "" {
-		return fmt.Errorf("token is empty")
-	}
-
-	if time.Since(c.lastKeepAlive) < 5*time.Minute {
-		return nil
-	}
-
 	l.Debug("Refreshing token")
 
 	url, err := c.getUrl(keepAliveUrlPath)
 	if err != nil {
-		return err
+		return bearerToken{}, err
 	}
 
 	request, err := c.wrapper.NewRequest(
@@ -106,41 +175,39 @@ func (c *Client) keepAliveToken(
 		uhttp.WithContentTypeJSONHeader(),
 		uhttp.WithHeader(
 			"Authorization",
-			fmt.Sprintf("Bearer %s", c.token),
+			fmt.Sprintf("Bearer %s", token),
 		),
 	)
 	if err != nil {
-		return err
+		return bearerToken{}, err
 	}
 
+	issuedAt := time.Now()
 	var target TokenResponse
 	response, err := c.wrapper.Do(request, uhttp.WithJSONResponse(&target))
 	if err != nil {
-		return err
+		return bearerToken{}, err
 	}
 	err = response.Body.Close()
 	if err != nil {
-		return err
+		return bearerToken{}, err
 	}
 
-	c.token = target.Token
-	c.lastKeepAlive = time.Now()
-
-	return nil
+	return target.bearerToken(issuedAt), nil
 }
 
-// CreateBearerToken creates bearer token needed to use the Jamf API.
-func (c *Client) CreateBearerToken(
+// createBearerToken creates a bearer token for a Jamf Pro user account.
+func (c *Client) createBearerToken(
 	ctx context.Context,
 	username string,
 	password string,
-) (string, error) {
+) (bearerToken, error) {
 	l := ctxzap.Extract(ctx)
 
 	l.Debug("Creating bearer token")
 	url, err := c.getUrl(tokenUrlPath)
 	if err != nil {
-		return "", err
+		return bearerToken{}, err
 	}
 
 	request, err := c.wrapper.NewRequest(
@@ -151,20 +218,65 @@ func (c *Client) CreateBearerToken(
 		uhttp.WithContentTypeJSONHeader(),
 	)
 	if err != nil {
-		return "", err
+		return bearerToken{}, err
 	}
 	request.SetBasicAuth(username, password)
 
+	issuedAt := time.Now()
 	var target TokenResponse
 	response, err := c.wrapper.Do(request, uhttp.WithJSONResponse(&target))
 	if err != nil {
-		return "", err
+		return bearerToken{}, err
+	}
+	err = response.Body.Close()
+	if err != nil {
+		return bearerToken{}, err
+	}
+	return target.bearerToken(issuedAt), nil
+}
+
+// createOAuthToken creates a bearer token for a Jamf Pro API client with the
+// OAuth client_credentials grant.
+func (c *Client) createOAuthToken(
+	ctx context.Context,
+	clientID string,
+	clientSecret string,
+) (bearerToken, error) {
+	l := ctxzap.Extract(ctx)
+
+	l.Debug("Creating API client token")
+	url, err := c.getUrl(oauthTokenUrlPath)
+	if err != nil {
+		return bearerToken{}, err
+	}
+
+	form := liburl.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", clientID)
+	form.Set("client_secret", clientSecret)
+
+	request, err := c.wrapper.NewRequest(
+		ctx,
+		http.MethodPost,
+		url,
+		uhttp.WithAcceptJSONHeader(),
+		uhttp.WithFormBody(form.Encode()),
+	)
+	if err != nil {
+		return bearerToken{}, err
+	}
+
+	issuedAt := time.Now()
+	var target OAuthTokenResponse
+	response, err := c.wrapper.Do(request, uhttp.WithJSONResponse(&target))
+	if err != nil {
+		return bearerToken{}, err
 	}
 	err = response.Body.Close()
 	if err != nil {
-		return "", err
+		return bearerToken{}, err
 	}
-	return target.Token, nil
+	return newBearerToken(target.AccessToken, issuedAt, time.Duration(target.ExpiresIn)*time.Second), nil
 }
 
 // GetTokenDetails gets authorization details associated with the current api token.
@@ -196,7 +308,8 @@ func (c *Client) getBaseUsers(ctx context.Context) ([]BaseType, error) {
 	return target.Users, nil
 }
 
-func (c *Client) getUserDetails(ctx context.Context, userId int) (*User, error) {
+// GetUserDetails returns the Jamf user with the given ID.
+func (c *Client) GetUserDetails(ctx context.Context, userId int) (*User, error) {
 	url, err := c.getUrl(fmt.Sprintf(userUrlPath, userId))
 	if err != nil {
 		return nil, err
@@ -239,6 +352,19 @@ func (c *Client) GetGroupDetails(ctx context.Context, groupId int) (*Group, erro
 	return &target.Group, nil
 }
 
+// UpdateGroup applies a partial update to the Jamf admin group with the given
+// ID. Returns a gRPC NotFound error (surfaced via IsNotFoundError) if the
+// group doesn't exist.
+func (c *Client) UpdateGroup(ctx context.Context, groupId int, update GroupUpdateBody) error {
+	defer c.cache.accounts.invalidate()
+	url, err := c.getUrl(fmt.Sprintf(groupUrlPath, groupId))
+	if err != nil {
+		return err
+	}
+
+	return c.doRequestWithMethod(ctx, http.MethodPut, url, update, nil)
+}
+
 // GetUserAccountDetails returns Jamf user account details.
 func (c *Client) GetUserAccountDetails(ctx context.Context, userId int) (*UserAccount, error) {
 	url, err := c.getUrl(fmt.Sprintf(accountUrlPath, userId))
@@ -254,8 +380,24 @@ func (c *Client) GetUserAccountDetails(ctx context.Context, userId int) (*UserAc
 	return &target.UserAccount, nil
 }
 
-// GetSites returns all Jamf sites.
+// GetSites returns all Jamf sites, memoized for the sync in ctx (see
+// WithSyncID).
 func (c *Client) GetSites(ctx context.Context) (*[]Site, error) {
+	return c.cache.sites.get(ctx, c.getSites)
+}
+
+// GetSitesPage returns up to limit Jamf sites starting at offset, and the
+// total number of sites. Sites come back whole from a single request, so a
+// page is a slice of the GetSites listing.
+func (c *Client) GetSitesPage(ctx context.Context, offset, limit int) ([]Site, int, error) {
+	sites, err := c.GetSites(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return pageOf(*sites, offset, limit), len(*sites), nil
+}
+
+func (c *Client) getSites(ctx context.Context) (*[]Site, error) {
 	url, err := c.getUrl(sitesUrlPath)
 	if err != nil {
 		return nil, err
@@ -299,72 +441,201 @@ func (c *Client) GetUserGroupDetails(ctx context.Context, userGroupId int) (*Use
 	return &target.UserGroup, nil
 }
 
-// GetUsers returns all Jamf users.
+// AddUserGroupMember adds the Jamf user with the given ID to a static user
+// group. Returns a gRPC NotFound error (surfaced via IsNotFoundError) if the
+// group doesn't exist.
+func (c *Client) AddUserGroupMember(ctx context.Context, userGroupId int, userId int) error {
+	return c.UpdateUserGroup(ctx, userGroupId, UserGroupUpdateBody{
+		UserAdditions: &UserGroupMembers{Users: []IDRef{{ID: userId}}},
+	})
+}
+
+// RemoveUserGroupMember removes the Jamf user with the given ID from a
+// static user group. Returns a gRPC NotFound error (surfaced via
+// IsNotFoundError) if the group doesn't exist.
+func (c *Client) RemoveUserGroupMember(ctx context.Context, userGroupId int, userId int) error {
+	return c.UpdateUserGroup(ctx, userGroupId, UserGroupUpdateBody{
+		UserDeletions: &UserGroupMembers{Users: []IDRef{{ID: userId}}},
+	})
+}
+
+// UpdateUserGroup applies a partial update to the Jamf user group with the
+// given ID. Returns a gRPC NotFound error (surfaced via IsNotFoundError) if
+// the group doesn't exist.
+func (c *Client) UpdateUserGroup(ctx context.Context, userGroupId int, update UserGroupUpdateBody) error {
+	defer c.cache.userGroups.invalidate()
+	url, err := c.getUrl(fmt.Sprintf(userGroupUrlPath, userGroupId))
+	if err != nil {
+		return err
+	}
+
+	return c.doRequestWithMethod(ctx, http.MethodPut, url, update, nil)
+}
+
+// GetUsers returns all Jamf users, memoized for the sync in ctx (see
+// WithSyncID).
 func (c *Client) GetUsers(ctx context.Context) ([]*User, error) {
-	var users []*User
+	return c.cache.users.get(ctx, c.getUsers)
+}
+
+func (c *Client) getUsers(ctx context.Context) ([]*User, error) {
 	baseUsers, err := c.getBaseUsers(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, baseUser := range baseUsers {
-		user, err := c.getUserDetails(ctx, baseUser.ID)
-		if err != nil {
-			return nil, err
-		}
-		users = append(users, user)
+	ids := make([]int, 0, len(baseUsers))
+	for _, user := range baseUsers {
+		ids = append(ids, user.ID)
 	}
 
-	return users, nil
+	return fetchDetails(ctx, c.concurrency, ids, c.GetUserDetails)
 }
 
-// GetUserGroups returns all Jamf user groups.
+// GetUserGroups returns all Jamf user groups, memoized for the sync in ctx
+// (see WithSyncID).
 func (c *Client) GetUserGroups(ctx context.Context) ([]*UserGroup, error) {
-	var userGroups []*UserGroup
-	baseUserGroup, err := c.getBaseUserGroups(ctx)
+	return c.cache.userGroups.get(ctx, c.getUserGroups)
+}
+
+func (c *Client) getUserGroups(ctx context.Context) ([]*UserGroup, error) {
+	baseUserGroups, err := c.getBaseUserGroups(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, userGroup := range baseUserGroup {
-		userGroupInfo, err := c.GetUserGroupDetails(ctx, userGroup.ID)
-		if err != nil {
-			return nil, err
-		}
-		userGroups = append(userGroups, userGroupInfo)
+	ids := make([]int, 0, len(baseUserGroups))
+	for _, userGroup := range baseUserGroups {
+		ids = append(ids, userGroup.ID)
 	}
 
-	return userGroups, nil
+	return fetchDetails(ctx, c.concurrency, ids, c.GetUserGroupDetails)
 }
 
-// GetAccounts returns all Jamf accounts.
-// TODO(marcos): The Jamf API doesn't have pagination, but this method could
-// benefit from parallelization.
+// GetAccounts returns all Jamf admin accounts and groups, memoized for the
+// sync in ctx (see WithSyncID).
 func (c *Client) GetAccounts(ctx context.Context) ([]*UserAccount, []*Group, error) {
-	var userAccounts []*UserAccount
-	var groups []*Group
-	baseAccounts, err := c.getBaseAccounts(ctx)
+	result, err := c.cache.accounts.get(ctx, c.getAccounts)
 	if err != nil {
 		return nil, nil, err
 	}
+	return result.userAccounts, result.groups, nil
+}
+
+func (c *Client) getAccounts(ctx context.Context) (accounts, error) {
+	baseAccounts, err := c.getBaseAccounts(ctx)
+	if err != nil {
+		return accounts{}, err
+	}
 
+	userIds := make([]int, 0, len(baseAccounts.Users))
 	for _, user := range baseAccounts.Users {
-		userAccountInfo, err := c.GetUserAccountDetails(ctx, user.ID)
-		if err != nil {
-			return nil, nil, err
-		}
-		userAccounts = append(userAccounts, userAccountInfo)
+		userIds = append(userIds, user.ID)
+	}
+	userAccounts, err := fetchDetails(ctx, c.concurrency, userIds, c.GetUserAccountDetails)
+	if err != nil {
+		return accounts{}, err
 	}
 
+	groupIds := make([]int, 0, len(baseAccounts.Groups))
 	for _, group := range baseAccounts.Groups {
-		groupInfo, err := c.GetGroupDetails(ctx, group.ID)
-		if err != nil {
-			return nil, nil, err
-		}
-		groups = append(groups, groupInfo)
+		groupIds = append(groupIds, group.ID)
+	}
+	groups, err := fetchDetails(ctx, c.concurrency, groupIds, c.GetGroupDetails)
+	if err != nil {
+		return accounts{}, err
+	}
+
+	return accounts{userAccounts: userAccounts, groups: groups}, nil
+}
+
+// GetUsersPage returns the details of up to limit Jamf users starting at
+// offset, and the total number of users. Users are ordered by ID, and the
+// ID list is memoized for the sync in ctx (see WithSyncID) so offsets stay
+// stable from one page to the next.
+func (c *Client) GetUsersPage(ctx context.Context, offset, limit int) ([]*User, int, error) {
+	ids, err := c.cache.userIDs.get(ctx, c.getUserIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users, err := fetchDetails(ctx, c.concurrency, pageOf(ids, offset, limit), c.GetUserDetails)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, len(ids), nil
+}
+
+// GetUserGroupsPage returns the details of up to limit Jamf user groups
+// starting at offset, and the total number of user groups. See GetUsersPage.
+func (c *Client) GetUserGroupsPage(ctx context.Context, offset, limit int) ([]*UserGroup, int, error) {
+	ids, err := c.cache.userGroupIDs.get(ctx, c.getUserGroupIDs)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return userAccounts, groups, nil
+	userGroups, err := fetchDetails(ctx, c.concurrency, pageOf(ids, offset, limit), c.GetUserGroupDetails)
+	if err != nil {
+		return nil, 0, err
+	}
+	return userGroups, len(ids), nil
+}
+
+// GetGroupsPage returns the details of up to limit Jamf admin groups starting
+// at offset, and the total number of admin groups. See GetUsersPage.
+func (c *Client) GetGroupsPage(ctx context.Context, offset, limit int) ([]*Group, int, error) {
+	ids, err := c.cache.groupIDs.get(ctx, c.getGroupIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	groups, err := fetchDetails(ctx, c.concurrency, pageOf(ids, offset, limit), c.GetGroupDetails)
+	if err != nil {
+		return nil, 0, err
+	}
+	return groups, len(ids), nil
+}
+
+func (c *Client) getUserIDs(ctx context.Context) ([]int, error) {
+	baseUsers, err := c.getBaseUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(baseUsers))
+	for _, user := range baseUsers {
+		ids = append(ids, user.ID)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (c *Client) getUserGroupIDs(ctx context.Context) ([]int, error) {
+	baseUserGroups, err := c.getBaseUserGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(baseUserGroups))
+	for _, userGroup := range baseUserGroups {
+		ids = append(ids, userGroup.ID)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (c *Client) getGroupIDs(ctx context.Context) ([]int, error) {
+	baseAccounts, err := c.getBaseAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(baseAccounts.Groups))
+	for _, group := range baseAccounts.Groups {
+		ids = append(ids, group.ID)
+	}
+	slices.Sort(ids)
+	return ids, nil
 }
 
 // GetUserByName returns the Jamf user with the given username (login name).
@@ -382,9 +653,28 @@ func (c *Client) GetUserByName(ctx context.Context, name string) (*User, error)
 	return &target.User, nil
 }
 
+// GetUsersByEmail returns the Jamf users with the given email address. Jamf
+// doesn't require emails to be unique, so there may be several. Returns a
+// gRPC NotFound error (surfaced via IsNotFoundError) if there are none.
+func (c *Client) GetUsersByEmail(ctx context.Context, email string) ([]User, error) {
+	url, err := c.getUrl(fmt.Sprintf(userEmailUrlPath, liburl.PathEscape(email)))
+	if err != nil {
+		return nil, err
+	}
+
+	var target UsersByEmailResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return target.Users, nil
+}
+
 // CreateUser creates a new Jamf user. Returns a gRPC AlreadyExists error
 // (surfaced via IsAlreadyExistsError) if a user with this name already exists.
 func (c *Client) CreateUser(ctx context.Context, name, fullName, email string) error {
+	defer c.cache.users.invalidate()
+	defer c.cache.userIDs.invalidate()
 	url, err := c.getUrl(fmt.Sprintf(userUrlPath, newResourceID))
 	if err != nil {
 		return err
@@ -399,9 +689,25 @@ func (c *Client) CreateUser(ctx context.Context, name, fullName, email string) e
 	return c.doRequestWithMethod(ctx, http.MethodPost, url, reqBody, nil)
 }
 
+// UpdateUser applies a partial update to the Jamf user with the given ID.
+// Returns a gRPC NotFound error (surfaced via IsNotFoundError) if the user
+// doesn't exist.
+func (c *Client) UpdateUser(ctx context.Context, userID int, update UserUpdateBody) error {
+	defer c.cache.users.invalidate()
+	url, err := c.getUrl(fmt.Sprintf(userUrlPath, userID))
+	if err != nil {
+		return err
+	}
+
+	return c.doRequestWithMethod(ctx, http.MethodPut, url, update, nil)
+}
+
 // DeleteUser deletes the Jamf user with the given ID. Returns a gRPC NotFound
 // error (surfaced via IsNotFoundError) if the user doesn't exist.
 func (c *Client) DeleteUser(ctx context.Context, userID int) error {
+	defer c.cache.users.invalidate()
+	defer c.cache.userIDs.invalidate()
+	defer c.cache.userGroups.invalidate()
 	url, err := c.getUrl(fmt.Sprintf(userUrlPath, userID))
 	if err != nil {
 		return err
@@ -429,6 +735,7 @@ func (c *Client) GetUserAccountByName(ctx context.Context, name string) (*UserAc
 // gRPC AlreadyExists error (surfaced via IsAlreadyExistsError) if an account
 // with this name already exists.
 func (c *Client) CreateUserAccount(ctx context.Context, account UserAccountCreateBody) error {
+	defer c.cache.accounts.invalidate()
 	url, err := c.getUrl(fmt.Sprintf(accountUrlPath, newResourceID))
 	if err != nil {
 		return err
@@ -437,9 +744,23 @@ func (c *Client) CreateUserAccount(ctx context.Context, account UserAccountCreat
 	return c.doRequestWithMethod(ctx, http.MethodPost, url, account, nil)
 }
 
+// UpdateUserAccount applies a partial update to the Jamf admin account with
+// the given ID. Returns a gRPC NotFound error (surfaced via IsNotFoundError)
+// if the account doesn't exist.
+func (c *Client) UpdateUserAccount(ctx context.Context, accountID int, update UserAccountUpdateBody) error {
+	defer c.cache.accounts.invalidate()
+	url, err := c.getUrl(fmt.Sprintf(accountUrlPath, accountID))
+	if err != nil {
+		return err
+	}
+
+	return c.doRequestWithMethod(ctx, http.MethodPut, url, update, nil)
+}
+
 // DeleteUserAccount deletes the Jamf admin account with the given ID. Returns
 // a gRPC NotFound error (surfaced via IsNotFoundError) if the account doesn't exist.
 func (c *Client) DeleteUserAccount(ctx context.Context, accountID int) error {
+	defer c.cache.accounts.invalidate()
 	url, err := c.getUrl(fmt.Sprintf(accountUrlPath, accountID))
 	if err != nil {
 		return err
@@ -448,6 +769,12 @@ func (c *Client) DeleteUserAccount(ctx context.Context, accountID int) error {
 	return c.doRequestWithMethod(ctx, http.MethodDelete, url, nil, nil)
 }
 
+// jsonBody wraps a request body for the Jamf Pro API, which takes JSON
+// rather than the Classic API's XML.
+type jsonBody struct {
+	value interface{}
+}
+
 // doRequest performs an authenticated GET request to the Jamf API.
 func (c *Client) doRequest(
 	ctx context.Context,
@@ -459,9 +786,11 @@ func (c *Client) doRequest(
 
 // doRequestWithMethod performs an authenticated request to the Jamf API for
 // any HTTP method, optionally sending reqBody as an XML request body (the
-// only format the Classic API accepts for POST/PUT). Passing a nil target
+// only format the Classic API accepts for POST/PUT), or as JSON when it's
+// wrapped in a jsonBody. Passing a nil target
 // skips decoding the response body (used for DELETE and other no-content
-// responses).
+// responses). Idempotent requests that are rate limited or fail transiently
+// are retried according to the client's RetryPolicy.
 func (c *Client) doRequestWithMethod(
 	ctx context.Context,
 	method string,
@@ -471,27 +800,33 @@ func (c *Client) doRequestWithMethod(
 ) error {
 	l := ctxzap.Extract(ctx)
 
-	err := c.keepAliveToken(ctx)
+	token, err := c.tokens.Token(ctx)
 	if err != nil {
 		return err
 	}
 
 	firstTry := true
+	retries := c.retryPolicy.start(time.Now())
 
 GotoRetry:
 	requestOpts := []uhttp.RequestOption{
 		uhttp.WithAcceptJSONHeader(),
 		uhttp.WithHeader(
 			"Authorization",
-			fmt.Sprintf("Bearer %s", c.token),
+			fmt.Sprintf("Bearer %s", token),
 		),
 	}
-	if reqBody != nil {
+	if body, ok := reqBody.(jsonBody); ok {
+		requestOpts = append(requestOpts, uhttp.WithJSONBody(body.value))
+	} else if reqBody != nil {
 		// The Classic API only accepts XML for POST/PUT request bodies (JSON
 		// is GET-response-only); see
 		// https://developer.jamf.com/jamf-pro/docs/getting-started-2.
 		requestOpts = append(requestOpts, uhttp.WithXMLBody(reqBody))
 	}
+	if uncached, _ := ctx.Value(uncachedKey{}).(bool); uncached {
+		requestOpts = append(requestOpts, uhttp.WithNoCache())
+	}
 
 	request, err := c.wrapper.NewRequest(
 		ctx,
@@ -512,17 +847,32 @@ GotoRetry:
 		l.Debug("failed to perform request", zap.Error(err))
 		if status.Code(err) == codes.Unauthenticated && firstTry {
 			l.Debug("retrying request with new token")
-			token, err := c.CreateBearerToken(ctx, c.userName, c.password)
+			token, err = c.tokens.Reacquire(ctx, token)
 			if err != nil {
 				return err
 			}
-
-			c.SetBearerToken(token)
 			firstTry = false
 
 			l.Debug("retrying request with new token")
 			goto GotoRetry
 		}
+		if delay, ok := retries.next(method, response, err, time.Now()); ok {
+			l.Debug(
+				"retrying request after transient error",
+				zap.String("method", method),
+				zap.String("url", url.String()),
+				zap.Int("attempt", retries.attempt),
+				zap.Duration("delay", delay),
+			)
+			if err := sleep(ctx, delay); err != nil {
+				return err
+			}
+			// The wait may have outlasted the token.
+			if token, err = c.tokens.Token(ctx); err != nil {
+				return err
+			}
+			goto GotoRetry
+		}
 		return err
 	}
 
@@ -0,0 +1,75 @@
+package jamf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+func newTestClient(t *testing.T, serverURL string, credentials Credentials) *Client {
+	t.Helper()
+	httpClient, err := uhttp.NewClient(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return NewClient(uhttp.NewBaseHttpClient(httpClient), credentials, serverURL)
+}
+
+// TestClient_APIClientReauthenticatesOnUnauthorized checks that an API client
+// goes back through the client_credentials grant — not basic auth — when a
+// request is rejected with 401.
+func TestClient_APIClientReauthenticatesOnUnauthorized(t *testing.T) {
+	var issued atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc(oauthTokenUrlPath, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("client_id") != "id" || r.PostForm.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		n := issued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(OAuthTokenResponse{AccessToken: fmt.Sprintf("token-%d", n), TokenType: "Bearer", ExpiresIn: 60})
+	})
+	mux.HandleFunc(tokenUrlPath, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("an API client must not request a user token")
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc(sitesUrlPath, func(w http.ResponseWriter, r *http.Request) {
+		// Only the second token is accepted, so the first request has to
+		// re-authenticate.
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SitesResponse{Sites: []Site{{BaseType{ID: 1, Name: "Headquarters"}}}})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := newTestClient(t, server.URL, Credentials{ClientID: "id", ClientSecret: "secret"})
+	if err := client.Authenticate(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sites, err := client.GetSites(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*sites) != 1 {
+		t.Errorf("expected 1 site, got %d", len(*sites))
+	}
+	if got := issued.Load(); got != 2 {
+		t.Errorf("expected 2 tokens to be issued, got %d", got)
+	}
+}
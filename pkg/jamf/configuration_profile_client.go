@@ -0,0 +1,119 @@
+package jamf
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+const (
+	macOSConfigurationProfileUrlPath  = "/JSSResource/osxconfigurationprofiles/id/%d"
+	macOSConfigurationProfilesUrlPath = "/JSSResource/osxconfigurationprofiles"
+
+	mobileDeviceConfigurationProfileUrlPath  = "/JSSResource/mobiledeviceconfigurationprofiles/id/%d"
+	mobileDeviceConfigurationProfilesUrlPath = "/JSSResource/mobiledeviceconfigurationprofiles"
+)
+
+// GetMacOSConfigurationProfileDetails returns the macOS configuration profile
+// with the given ID, including its scope. Requires the "Read macOS
+// Configuration Profiles" privilege.
+func (c *Client) GetMacOSConfigurationProfileDetails(ctx context.Context, profileId int) (*MacOSConfigurationProfile, error) {
+	url, err := c.getUrl(fmt.Sprintf(macOSConfigurationProfileUrlPath, profileId))
+	if err != nil {
+		return nil, err
+	}
+
+	var target MacOSConfigurationProfileResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return &target.ConfigurationProfile, nil
+}
+
+// GetMacOSConfigurationProfilesPage returns the details of up to limit macOS
+// configuration profiles starting at offset, and the total number of them.
+// See GetUsersPage.
+func (c *Client) GetMacOSConfigurationProfilesPage(ctx context.Context, offset, limit int) ([]*MacOSConfigurationProfile, int, error) {
+	ids, err := c.cache.macOSConfigurationProfileIDs.get(ctx, c.getMacOSConfigurationProfileIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	profiles, err := fetchDetails(ctx, c.concurrency, pageOf(ids, offset, limit), c.GetMacOSConfigurationProfileDetails)
+	if err != nil {
+		return nil, 0, err
+	}
+	return profiles, len(ids), nil
+}
+
+func (c *Client) getMacOSConfigurationProfileIDs(ctx context.Context) ([]int, error) {
+	url, err := c.getUrl(macOSConfigurationProfilesUrlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var target MacOSConfigurationProfilesResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return sortedIDs(target.ConfigurationProfiles), nil
+}
+
+// GetMobileDeviceConfigurationProfileDetails returns the mobile device
+// configuration profile with the given ID, including its scope. Requires the
+// "Read iOS Configuration Profiles" privilege.
+func (c *Client) GetMobileDeviceConfigurationProfileDetails(ctx context.Context, profileId int) (*MobileDeviceConfigurationProfile, error) {
+	url, err := c.getUrl(fmt.Sprintf(mobileDeviceConfigurationProfileUrlPath, profileId))
+	if err != nil {
+		return nil, err
+	}
+
+	var target MobileDeviceConfigurationProfileResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return &target.ConfigurationProfile, nil
+}
+
+// GetMobileDeviceConfigurationProfilesPage returns the details of up to limit
+// mobile device configuration profiles starting at offset, and the total
+// number of them. See GetUsersPage.
+func (c *Client) GetMobileDeviceConfigurationProfilesPage(ctx context.Context, offset, limit int) ([]*MobileDeviceConfigurationProfile, int, error) {
+	ids, err := c.cache.mobileDeviceConfigurationProfileIDs.get(ctx, c.getMobileDeviceConfigurationProfileIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	profiles, err := fetchDetails(ctx, c.concurrency, pageOf(ids, offset, limit), c.GetMobileDeviceConfigurationProfileDetails)
+	if err != nil {
+		return nil, 0, err
+	}
+	return profiles, len(ids), nil
+}
+
+func (c *Client) getMobileDeviceConfigurationProfileIDs(ctx context.Context) ([]int, error) {
+	url, err := c.getUrl(mobileDeviceConfigurationProfilesUrlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var target MobileDeviceConfigurationProfilesResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return sortedIDs(target.ConfigurationProfiles), nil
+}
+
+// sortedIDs returns the IDs of refs in ascending order.
+func sortedIDs(refs []BaseType) []int {
+	ids := make([]int, 0, len(refs))
+	for _, ref := range refs {
+		ids = append(ids, ref.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
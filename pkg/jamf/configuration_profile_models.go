@@ -0,0 +1,60 @@
+package jamf
+
+// MobileDeviceScopeTargets are the targets a mobile device scope (or its
+// exclusions) lists.
+type MobileDeviceScopeTargets struct {
+	MobileDevices      []BaseType `json:"mobile_devices"`
+	MobileDeviceGroups []BaseType `json:"mobile_device_groups"`
+	Buildings          []BaseType `json:"buildings"`
+	Departments        []BaseType `json:"departments"`
+}
+
+// MobileDeviceScope is the <scope> block of a mobile device configuration
+// profile. It works like a ComputerScope.
+type MobileDeviceScope struct {
+	AllMobileDevices bool `json:"all_mobile_devices"`
+	MobileDeviceScopeTargets
+	Exclusions MobileDeviceScopeTargets `json:"exclusions"`
+}
+
+// ConfigurationProfileGeneral is the <general> block of a macOS or mobile
+// device configuration profile.
+type ConfigurationProfileGeneral struct {
+	BaseType
+	Description        string   `json:"description"`
+	Level              string   `json:"level"`
+	DistributionMethod string   `json:"distribution_method"`
+	UUID               string   `json:"uuid"`
+	Category           BaseType `json:"category"`
+	Site               BaseType `json:"site"`
+}
+
+// MacOSConfigurationProfile is a configuration profile installed on the
+// computers in its scope.
+type MacOSConfigurationProfile struct {
+	General ConfigurationProfileGeneral `json:"general"`
+	Scope   ComputerScope               `json:"scope"`
+}
+
+// MobileDeviceConfigurationProfile is a configuration profile installed on
+// the mobile devices in its scope.
+type MobileDeviceConfigurationProfile struct {
+	General ConfigurationProfileGeneral `json:"general"`
+	Scope   MobileDeviceScope           `json:"scope"`
+}
+
+type MacOSConfigurationProfilesResponse struct {
+	ConfigurationProfiles []BaseType `json:"os_x_configuration_profiles"`
+}
+
+type MacOSConfigurationProfileResponse struct {
+	ConfigurationProfile MacOSConfigurationProfile `json:"os_x_configuration_profile"`
+}
+
+type MobileDeviceConfigurationProfilesResponse struct {
+	ConfigurationProfiles []BaseType `json:"configuration_profiles"`
+}
+
+type MobileDeviceConfigurationProfileResponse struct {
+	ConfigurationProfile MobileDeviceConfigurationProfile `json:"configuration_profile"`
+}
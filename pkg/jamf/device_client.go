@@ -2,13 +2,18 @@ package jamf
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	liburl "net/url"
 	"strconv"
 )
 
 const (
-	computersInventoryUrlPath = "/api/v1/computers-inventory"
-	mobileDevicesUrlPath      = "/api/v2/mobile-devices"
+	computersInventoryUrlPath      = "/api/v1/computers-inventory"
+	computerInventoryDetailUrlPath = "/api/v1/computers-inventory-detail/%d"
+	mobileDevicesUrlPath           = "/api/v2/mobile-devices"
+	mobileDeviceUrlPath            = "/api/v2/mobile-devices/%d"
+	mobileDevicesDetailPath        = "/api/v2/mobile-devices/detail"
 )
 
 // ComputerInventorySections are the inventory sections the connector requests.
@@ -21,6 +26,7 @@ var ComputerInventorySections = []string{
 	"USER_AND_LOCATION",
 	"DISK_ENCRYPTION",
 	"SECURITY",
+	"LOCAL_USER_ACCOUNTS",
 }
 
 // GetComputersInventory returns a single page of the computers inventory.
@@ -78,3 +84,86 @@ func (c *Client) GetMobileDevices(
 
 	return &target, nil
 }
+
+// GetComputerLocations returns every computer in the inventory with only its
+// USER_AND_LOCATION section, memoized for the sync in ctx (see WithSyncID).
+// Scopes that target all computers, buildings or departments are resolved
+// against it.
+func (c *Client) GetComputerLocations(ctx context.Context) ([]ComputerInventory, error) {
+	return c.cache.computerLocations.get(ctx, func(ctx context.Context) ([]ComputerInventory, error) {
+		return getAllPages[ComputerInventory](ctx, c, computersInventoryUrlPath, liburl.Values{"section": {"USER_AND_LOCATION"}})
+	})
+}
+
+// GetMobileDeviceLocations returns every mobile device with only its
+// USER_AND_LOCATION section, memoized for the sync in ctx (see WithSyncID).
+// It's the mobile device counterpart of GetComputerLocations.
+func (c *Client) GetMobileDeviceLocations(ctx context.Context) ([]MobileDeviceDetail, error) {
+	return c.cache.mobileDeviceLocations.get(ctx, func(ctx context.Context) ([]MobileDeviceDetail, error) {
+		return getAllPages[MobileDeviceDetail](ctx, c, mobileDevicesDetailPath, liburl.Values{"section": {"USER_AND_LOCATION"}})
+	})
+}
+
+// GetComputerInventoryDetail returns every inventory section of the computer
+// with the given ID. Returns a gRPC NotFound error (surfaced via
+// IsNotFoundError) if the computer doesn't exist.
+func (c *Client) GetComputerInventoryDetail(ctx context.Context, computerId int) (*ComputerInventory, error) {
+	url, err := c.getUrl(fmt.Sprintf(computerInventoryDetailUrlPath, computerId))
+	if err != nil {
+		return nil, err
+	}
+
+	var target ComputerInventory
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
+// UpdateComputerUserAndLocation applies a partial update to the
+// USER_AND_LOCATION section of the computer with the given ID. Requires the
+// "Update Computers" privilege. Returns a gRPC NotFound error (surfaced via
+// IsNotFoundError) if the computer doesn't exist.
+func (c *Client) UpdateComputerUserAndLocation(ctx context.Context, computerId int, update ComputerUserAndLocationUpdate) error {
+	defer c.cache.computerLocations.invalidate()
+	url, err := c.getUrl(fmt.Sprintf(computerInventoryDetailUrlPath, computerId))
+	if err != nil {
+		return err
+	}
+
+	body := ComputerInventoryUpdateBody{UserAndLocation: &update}
+	return c.doRequestWithMethod(ctx, http.MethodPatch, url, jsonBody{body}, nil)
+}
+
+// GetMobileDevice returns the mobile device with the given ID, as the v2 list
+// endpoint records it. Returns a gRPC NotFound error (surfaced via
+// IsNotFoundError) if the device doesn't exist.
+func (c *Client) GetMobileDevice(ctx context.Context, mobileDeviceId int) (*MobileDevice, error) {
+	url, err := c.getUrl(fmt.Sprintf(mobileDeviceUrlPath, mobileDeviceId))
+	if err != nil {
+		return nil, err
+	}
+
+	var target MobileDevice
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
+// UpdateMobileDeviceLocation applies a partial update to the user and
+// location of the mobile device with the given ID. Requires the "Update
+// Mobile Devices" privilege. Returns a gRPC NotFound error (surfaced via
+// IsNotFoundError) if the device doesn't exist.
+func (c *Client) UpdateMobileDeviceLocation(ctx context.Context, mobileDeviceId int, update MobileDeviceLocationUpdate) error {
+	defer c.cache.mobileDeviceLocations.invalidate()
+	url, err := c.getUrl(fmt.Sprintf(mobileDeviceUrlPath, mobileDeviceId))
+	if err != nil {
+		return err
+	}
+
+	body := MobileDeviceUpdateBody{Location: &update}
+	return c.doRequestWithMethod(ctx, http.MethodPatch, url, jsonBody{body}, nil)
+}
@@ -0,0 +1,194 @@
+package jamf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+const (
+	computerGroupUrlPath  = "/JSSResource/computergroups/id/%d"
+	computerGroupsUrlPath = "/JSSResource/computergroups"
+
+	mobileDeviceGroupUrlPath  = "/JSSResource/mobiledevicegroups/id/%d"
+	mobileDeviceGroupsUrlPath = "/JSSResource/mobiledevicegroups"
+)
+
+func (c *Client) getBaseComputerGroups(ctx context.Context) ([]ComputerGroup, error) {
+	url, err := c.getUrl(computerGroupsUrlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var target ComputerGroupsResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return target.ComputerGroups, nil
+}
+
+// GetComputerGroupDetails returns a Jamf computer group with its members.
+// Requires the "Read Smart Computer Groups" and "Read Static Computer Groups"
+// privileges.
+func (c *Client) GetComputerGroupDetails(ctx context.Context, computerGroupId int) (*ComputerGroup, error) {
+	url, err := c.getUrl(fmt.Sprintf(computerGroupUrlPath, computerGroupId))
+	if err != nil {
+		return nil, err
+	}
+
+	var target ComputerGroupResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return &target.ComputerGroup, nil
+}
+
+// AddComputerGroupMember adds the computer with the given ID to a static
+// computer group. Returns a gRPC NotFound error (surfaced via
+// IsNotFoundError) if the group doesn't exist.
+func (c *Client) AddComputerGroupMember(ctx context.Context, computerGroupId int, computerId int) error {
+	return c.UpdateComputerGroup(ctx, computerGroupId, ComputerGroupUpdateBody{
+		ComputerAdditions: &ComputerGroupMembers{Computers: []IDRef{{ID: computerId}}},
+	})
+}
+
+// RemoveComputerGroupMember removes the computer with the given ID from a
+// static computer group. Returns a gRPC NotFound error (surfaced via
+// IsNotFoundError) if the group doesn't exist.
+func (c *Client) RemoveComputerGroupMember(ctx context.Context, computerGroupId int, computerId int) error {
+	return c.UpdateComputerGroup(ctx, computerGroupId, ComputerGroupUpdateBody{
+		ComputerDeletions: &ComputerGroupMembers{Computers: []IDRef{{ID: computerId}}},
+	})
+}
+
+// UpdateComputerGroup applies a partial update to the Jamf computer group
+// with the given ID. Requires the "Update Static Computer Groups" privilege.
+// Returns a gRPC NotFound error (surfaced via IsNotFoundError) if the group
+// doesn't exist.
+func (c *Client) UpdateComputerGroup(ctx context.Context, computerGroupId int, update ComputerGroupUpdateBody) error {
+	defer c.cache.computerGroups.invalidate()
+	url, err := c.getUrl(fmt.Sprintf(computerGroupUrlPath, computerGroupId))
+	if err != nil {
+		return err
+	}
+
+	return c.doRequestWithMethod(ctx, http.MethodPut, url, update, nil)
+}
+
+// GetComputerGroupsPage returns the details of up to limit Jamf computer
+// groups starting at offset, and the total number of computer groups. See
+// GetUsersPage.
+func (c *Client) GetComputerGroupsPage(ctx context.Context, offset, limit int) ([]*ComputerGroup, int, error) {
+	ids, err := c.cache.computerGroupIDs.get(ctx, c.getComputerGroupIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	computerGroups, err := fetchDetails(ctx, c.concurrency, pageOf(ids, offset, limit), c.GetComputerGroupDetails)
+	if err != nil {
+		return nil, 0, err
+	}
+	return computerGroups, len(ids), nil
+}
+
+// GetComputerGroups returns every Jamf computer group with its members,
+// memoized for the sync in ctx (see WithSyncID).
+func (c *Client) GetComputerGroups(ctx context.Context) ([]*ComputerGroup, error) {
+	return c.cache.computerGroups.get(ctx, func(ctx context.Context) ([]*ComputerGroup, error) {
+		ids, err := c.cache.computerGroupIDs.get(ctx, c.getComputerGroupIDs)
+		if err != nil {
+			return nil, err
+		}
+		return fetchDetails(ctx, c.concurrency, ids, c.GetComputerGroupDetails)
+	})
+}
+
+func (c *Client) getComputerGroupIDs(ctx context.Context) ([]int, error) {
+	baseComputerGroups, err := c.getBaseComputerGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(baseComputerGroups))
+	for _, computerGroup := range baseComputerGroups {
+		ids = append(ids, computerGroup.ID)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (c *Client) getBaseMobileDeviceGroups(ctx context.Context) ([]MobileDeviceGroup, error) {
+	url, err := c.getUrl(mobileDeviceGroupsUrlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var target MobileDeviceGroupsResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return target.MobileDeviceGroups, nil
+}
+
+// GetMobileDeviceGroupDetails returns a Jamf mobile device group with its
+// members. Requires the "Read Smart Mobile Device Groups" and "Read Static
+// Mobile Device Groups" privileges.
+func (c *Client) GetMobileDeviceGroupDetails(ctx context.Context, mobileDeviceGroupId int) (*MobileDeviceGroup, error) {
+	url, err := c.getUrl(fmt.Sprintf(mobileDeviceGroupUrlPath, mobileDeviceGroupId))
+	if err != nil {
+		return nil, err
+	}
+
+	var target MobileDeviceGroupResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return &target.MobileDeviceGroup, nil
+}
+
+// GetMobileDeviceGroupsPage returns the details of up to limit Jamf mobile
+// device groups starting at offset, and the total number of mobile device
+// groups. See GetUsersPage.
+func (c *Client) GetMobileDeviceGroupsPage(ctx context.Context, offset, limit int) ([]*MobileDeviceGroup, int, error) {
+	ids, err := c.cache.mobileDeviceGroupIDs.get(ctx, c.getMobileDeviceGroupIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	mobileDeviceGroups, err := fetchDetails(ctx, c.concurrency, pageOf(ids, offset, limit), c.GetMobileDeviceGroupDetails)
+	if err != nil {
+		return nil, 0, err
+	}
+	return mobileDeviceGroups, len(ids), nil
+}
+
+// GetMobileDeviceGroups returns every Jamf mobile device group with its
+// members, memoized for the sync in ctx (see WithSyncID).
+func (c *Client) GetMobileDeviceGroups(ctx context.Context) ([]*MobileDeviceGroup, error) {
+	return c.cache.mobileDeviceGroups.get(ctx, func(ctx context.Context) ([]*MobileDeviceGroup, error) {
+		ids, err := c.cache.mobileDeviceGroupIDs.get(ctx, c.getMobileDeviceGroupIDs)
+		if err != nil {
+			return nil, err
+		}
+		return fetchDetails(ctx, c.concurrency, ids, c.GetMobileDeviceGroupDetails)
+	})
+}
+
+func (c *Client) getMobileDeviceGroupIDs(ctx context.Context) ([]int, error) {
+	baseMobileDeviceGroups, err := c.getBaseMobileDeviceGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(baseMobileDeviceGroups))
+	for _, mobileDeviceGroup := range baseMobileDeviceGroups {
+		ids = append(ids, mobileDeviceGroup.ID)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
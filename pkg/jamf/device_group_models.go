@@ -0,0 +1,67 @@
+package jamf
+
+import "encoding/xml"
+
+// ComputerGroup is a Jamf computer group. Smart groups compute their members
+// from criteria; static groups list them explicitly.
+type ComputerGroup struct {
+	BaseType
+	IsSmart   bool                  `json:"is_smart"`
+	Site      BaseType              `json:"site"`
+	Computers []ComputerGroupMember `json:"computers"`
+}
+
+// ComputerGroupMember is a computer listed in a computer group. ID is the
+// same ID the computers inventory reports for the device.
+type ComputerGroupMember struct {
+	BaseType
+	SerialNumber string `json:"serial_number"`
+}
+
+// ComputerGroupUpdateBody is the XML request body for PUT
+// /JSSResource/computergroups/id/{id}. computer_additions and
+// computer_deletions change a static group's membership incrementally, like
+// UserGroupUpdateBody's user_additions and user_deletions.
+type ComputerGroupUpdateBody struct {
+	XMLName           xml.Name              `xml:"computer_group"`
+	ComputerAdditions *ComputerGroupMembers `xml:"computer_additions,omitempty"`
+	ComputerDeletions *ComputerGroupMembers `xml:"computer_deletions,omitempty"`
+}
+
+// ComputerGroupMembers is a <computer_additions>/<computer_deletions> block.
+type ComputerGroupMembers struct {
+	Computers []IDRef `xml:"computer"`
+}
+
+type ComputerGroupsResponse struct {
+	ComputerGroups []ComputerGroup `json:"computer_groups"`
+}
+
+type ComputerGroupResponse struct {
+	ComputerGroup ComputerGroup `json:"computer_group"`
+}
+
+// MobileDeviceGroup is a Jamf mobile device group. Like a ComputerGroup it is
+// either smart or static.
+type MobileDeviceGroup struct {
+	BaseType
+	IsSmart       bool                      `json:"is_smart"`
+	Site          BaseType                  `json:"site"`
+	MobileDevices []MobileDeviceGroupMember `json:"mobile_devices"`
+}
+
+// MobileDeviceGroupMember is a mobile device listed in a mobile device group.
+// ID is the same ID /api/v2/mobile-devices reports for the device.
+type MobileDeviceGroupMember struct {
+	BaseType
+	SerialNumber string `json:"serial_number"`
+	UDID         string `json:"udid"`
+}
+
+type MobileDeviceGroupsResponse struct {
+	MobileDeviceGroups []MobileDeviceGroup `json:"mobile_device_groups"`
+}
+
+type MobileDeviceGroupResponse struct {
+	MobileDeviceGroup MobileDeviceGroup `json:"mobile_device_group"`
+}
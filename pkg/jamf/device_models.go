@@ -14,7 +14,7 @@ type ComputersInventoryResponse struct {
 
 // ComputerInventory is a single computer record. The nested sections are only
 // populated when the matching `section` query parameter is requested, so every
-// section is a pointer that may be nil.
+// section is a pointer (or slice) that may be nil.
 type ComputerInventory struct {
 	ID              string                   `json:"id"`
 	UDID            string                   `json:"udid"`
@@ -24,11 +24,14 @@ type ComputerInventory struct {
 	UserAndLocation *ComputerUserAndLocation `json:"userAndLocation"`
 	DiskEncryption  *ComputerDiskEncryption  `json:"diskEncryption"`
 	Security        *ComputerSecurity        `json:"security"`
+
+	LocalUserAccounts []ComputerLocalUserAccount `json:"localUserAccounts"`
 }
 
 // ComputerGeneral holds the GENERAL section.
 type ComputerGeneral struct {
 	Name             string                    `json:"name"`
+	ManagementID     string                    `json:"managementId"`
 	LastEnrolledDate string                    `json:"lastEnrolledDate"`
 	Supervised       bool                      `json:"supervised"`
 	MDMCapable       *ComputerMDMCapable       `json:"mdmCapable"`
@@ -110,6 +113,17 @@ type ComputerSecurity struct {
 	ExternalBootLevel     string `json:"externalBootLevel"`
 }
 
+// ComputerLocalUserAccount is an entry of the LOCAL_USER_ACCOUNTS section: a
+// local macOS account on the computer, as of its last inventory update.
+type ComputerLocalUserAccount struct {
+	UID           string `json:"uid"`
+	UserGUID      string `json:"userGuid"`
+	Username      string `json:"username"`
+	FullName      string `json:"fullName"`
+	Admin         bool   `json:"admin"`
+	HomeDirectory string `json:"homeDirectory"`
+}
+
 // NamedRef is a generic {id,name} reference used by several Jamf sections.
 type NamedRef struct {
 	ID   string `json:"id"`
@@ -131,6 +145,7 @@ type MobileDevice struct {
 	Name            string `json:"name"`
 	SerialNumber    string `json:"serialNumber"`
 	UDID            string `json:"udid"`
+	ManagementID    string `json:"managementId"`
 	Model           string `json:"model"`
 	ModelIdentifier string `json:"modelIdentifier"`
 	Username        string `json:"username"`
@@ -142,3 +157,47 @@ type MobileDevice struct {
 	WifiMacAddress  string `json:"wifiMacAddress"`
 	PhoneNumber     string `json:"phoneNumber"`
 }
+
+// MobileDeviceDetail is a mobile-device record from the v2 detail endpoint
+// (GET /api/v2/mobile-devices/detail). Like ComputerInventory, its sections
+// are only populated when requested.
+type MobileDeviceDetail struct {
+	MobileDeviceID  string                       `json:"mobileDeviceId"`
+	UserAndLocation *MobileDeviceUserAndLocation `json:"userAndLocation"`
+}
+
+// MobileDeviceUserAndLocation holds the USER_AND_LOCATION section.
+type MobileDeviceUserAndLocation struct {
+	Username     string `json:"username"`
+	EmailAddress string `json:"emailAddress"`
+	DepartmentID string `json:"departmentId"`
+	BuildingID   string `json:"buildingId"`
+}
+
+// ComputerInventoryUpdateBody is the body of
+// PATCH /api/v1/computers-inventory-detail/{id}.
+type ComputerInventoryUpdateBody struct {
+	UserAndLocation *ComputerUserAndLocationUpdate `json:"userAndLocation,omitempty"`
+}
+
+// ComputerUserAndLocationUpdate is a partial update of the USER_AND_LOCATION
+// section. Nil fields are left unchanged; an empty string clears the field.
+type ComputerUserAndLocationUpdate struct {
+	Username *string `json:"username,omitempty"`
+	Realname *string `json:"realname,omitempty"`
+	Email    *string `json:"email,omitempty"`
+}
+
+// MobileDeviceUpdateBody is the body of PATCH /api/v2/mobile-devices/{id}.
+type MobileDeviceUpdateBody struct {
+	Location *MobileDeviceLocationUpdate `json:"location,omitempty"`
+}
+
+// MobileDeviceLocationUpdate is a partial update of a mobile device's user
+// and location, with the same nil and empty semantics as
+// ComputerUserAndLocationUpdate.
+type MobileDeviceLocationUpdate struct {
+	Username     *string `json:"username,omitempty"`
+	RealName     *string `json:"realName,omitempty"`
+	EmailAddress *string `json:"emailAddress,omitempty"`
+}
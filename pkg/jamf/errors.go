@@ -17,3 +17,10 @@ func IsNotFoundError(err error) bool {
 func IsAlreadyExistsError(err error) bool {
 	return status.Code(err) == codes.AlreadyExists
 }
+
+// IsInvalidRequestError reports whether err came from a Jamf API response
+// mapped to a 400 Bad Request, e.g. an update naming a privilege Jamf doesn't
+// recognize in the given category.
+func IsInvalidRequestError(err error) bool {
+	return status.Code(err) == codes.InvalidArgument
+}
@@ -0,0 +1,47 @@
+package jamf
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	ldapServerUrlPath  = "/JSSResource/ldapservers/id/%d"
+	ldapServersUrlPath = "/JSSResource/ldapservers"
+)
+
+// GetLDAPServers returns every LDAP server configured in Jamf. Requires the
+// "Read LDAP Servers" privilege.
+func (c *Client) GetLDAPServers(ctx context.Context) ([]*LDAPServer, error) {
+	url, err := c.getUrl(ldapServersUrlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var target LDAPServersResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(target.LDAPServers))
+	for _, server := range target.LDAPServers {
+		ids = append(ids, server.ID)
+	}
+
+	return fetchDetails(ctx, c.concurrency, ids, c.GetLDAPServerDetails)
+}
+
+// GetLDAPServerDetails returns the LDAP server with the given ID.
+func (c *Client) GetLDAPServerDetails(ctx context.Context, ldapServerId int) (*LDAPServer, error) {
+	url, err := c.getUrl(fmt.Sprintf(ldapServerUrlPath, ldapServerId))
+	if err != nil {
+		return nil, err
+	}
+
+	var target LDAPServerResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return &target.LDAPServer, nil
+}
@@ -0,0 +1,20 @@
+package jamf
+
+import "context"
+
+const (
+	buildingsUrlPath   = "/api/v1/buildings"
+	departmentsUrlPath = "/api/v1/departments"
+)
+
+// GetBuildings returns all Jamf Pro buildings. Requires the "Read Buildings"
+// privilege.
+func (c *Client) GetBuildings(ctx context.Context) ([]Building, error) {
+	return getAllPages[Building](ctx, c, buildingsUrlPath, nil)
+}
+
+// GetDepartments returns all Jamf Pro departments. Requires the "Read
+// Departments" privilege.
+func (c *Client) GetDepartments(ctx context.Context) ([]Department, error) {
+	return getAllPages[Department](ctx, c, departmentsUrlPath, nil)
+}
@@ -0,0 +1,21 @@
+package jamf
+
+// Building is a Jamf Pro building. Computers and mobile devices are placed in
+// one through their user and location details.
+type Building struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	StreetAddress1 string `json:"streetAddress1"`
+	StreetAddress2 string `json:"streetAddress2"`
+	City           string `json:"city"`
+	StateProvince  string `json:"stateProvince"`
+	ZipPostalCode  string `json:"zipPostalCode"`
+	Country        string `json:"country"`
+}
+
+// Department is a Jamf Pro department. Like buildings, devices are placed in
+// one through their user and location details.
+type Department struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,76 @@
+package jamf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+const (
+	mdmCommandsUrlPath      = "/api/v2/mdm/commands"
+	removeMDMProfileUrlPath = "/api/v1/computer-inventory/%d/remove-mdm-profile"
+	renewMDMProfileUrlPath  = "/api/v1/mdm/renew-profile"
+)
+
+// SendMDMCommand queues an MDM command for the device with the given
+// management ID and returns the command's UUID. Requires the privilege to
+// send the command to the device type, e.g. "Send Computer Remote Lock
+// Command".
+func (c *Client) SendMDMCommand(ctx context.Context, managementId string, command MDMCommandData) (string, error) {
+	url, err := c.getUrl(mdmCommandsUrlPath)
+	if err != nil {
+		return "", err
+	}
+
+	body := MDMCommandBody{
+		ClientData:  []MDMCommandClient{{ManagementID: managementId}},
+		CommandData: command,
+	}
+	var target []MDMCommandResult
+	if err := c.doRequestWithMethod(ctx, http.MethodPost, url, jsonBody{body}, &target); err != nil {
+		return "", err
+	}
+	if len(target) == 0 {
+		return "", fmt.Errorf("jamf returned no command for %s", command.CommandType)
+	}
+
+	return target[0].ID, nil
+}
+
+// RemoveComputerMDMProfile queues the removal of the MDM profile of the
+// computer with the given ID, which unmanages it, and returns the command's
+// UUID. Requires the "Send Computer Unmanage Command" privilege.
+func (c *Client) RemoveComputerMDMProfile(ctx context.Context, computerId int) (string, error) {
+	url, err := c.getUrl(fmt.Sprintf(removeMDMProfileUrlPath, computerId))
+	if err != nil {
+		return "", err
+	}
+
+	var target RemoveMDMProfileResponse
+	if err := c.doRequestWithMethod(ctx, http.MethodPost, url, nil, &target); err != nil {
+		return "", err
+	}
+
+	return target.CommandUUID, nil
+}
+
+// RenewMDMProfile queues the renewal of the MDM profile of the device with
+// the given UDID. Jamf doesn't return a command UUID for renewals.
+func (c *Client) RenewMDMProfile(ctx context.Context, udid string) error {
+	url, err := c.getUrl(renewMDMProfileUrlPath)
+	if err != nil {
+		return err
+	}
+
+	var target RenewMDMProfileResponse
+	body := RenewMDMProfileBody{UDIDs: []string{udid}}
+	if err := c.doRequestWithMethod(ctx, http.MethodPost, url, jsonBody{body}, &target); err != nil {
+		return err
+	}
+	if slices.Contains(target.UDIDsNotProcessed.UDIDs, udid) {
+		return fmt.Errorf("jamf didn't process the MDM profile renewal of device %s", udid)
+	}
+
+	return nil
+}
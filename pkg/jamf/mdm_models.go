@@ -0,0 +1,57 @@
+package jamf
+
+// MDM command types sent through POST /api/v2/mdm/commands.
+const (
+	MDMCommandDeviceLock    = "DEVICE_LOCK"
+	MDMCommandEraseDevice   = "ERASE_DEVICE"
+	MDMCommandRestartDevice = "RESTART_DEVICE"
+)
+
+// MDMCommandBody is the body of POST /api/v2/mdm/commands: one command sent
+// to the devices named by their management IDs.
+type MDMCommandBody struct {
+	ClientData  []MDMCommandClient `json:"clientData"`
+	CommandData MDMCommandData     `json:"commandData"`
+}
+
+// MDMCommandClient names a device by the management ID Jamf assigns it, which
+// differs from its inventory ID.
+type MDMCommandClient struct {
+	ManagementID string `json:"managementId"`
+}
+
+// MDMCommandData is a command and the options it takes. Pin is the six-digit
+// code a Mac asks for once locked or erased; iOS devices ignore it.
+type MDMCommandData struct {
+	CommandType string `json:"commandType"`
+	Pin         string `json:"pin,omitempty"`
+	Message     string `json:"message,omitempty"`
+	PhoneNumber string `json:"phoneNumber,omitempty"`
+}
+
+// MDMCommandResult is an entry of the POST /api/v2/mdm/commands response.
+// ID is the command UUID.
+type MDMCommandResult struct {
+	ID   string `json:"id"`
+	Href string `json:"href"`
+}
+
+// RemoveMDMProfileResponse is the response of
+// POST /api/v1/computer-inventory/{id}/remove-mdm-profile.
+type RemoveMDMProfileResponse struct {
+	DeviceID    string `json:"deviceId"`
+	CommandUUID string `json:"commandUuid"`
+}
+
+// RenewMDMProfileBody is the body of POST /api/v1/mdm/renew-profile.
+type RenewMDMProfileBody struct {
+	UDIDs []string `json:"udids"`
+}
+
+// RenewMDMProfileResponse lists the devices Jamf couldn't queue a renewal
+// for.
+type RenewMDMProfileResponse struct {
+	UDIDsNotProcessed struct {
+		UDIDs []string `json:"udids"`
+	} `json:"udidsNotProcessed"`
+}
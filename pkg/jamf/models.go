@@ -2,7 +2,9 @@ package jamf
 
 import (
 	"encoding/xml"
+	"fmt"
 	"slices"
+	"time"
 )
 
 type BaseType struct {
@@ -38,6 +40,11 @@ type UserAccount struct {
 	PrivilegeSet string     `json:"privilege_set"`
 	Privileges   Privileges `json:"privileges"`
 	Site         BaseType   `json:"site"`
+	Groups       []BaseType `json:"groups"`
+	// DirectoryUser is set for an account backed by LDAPServer rather than
+	// by a local Jamf password.
+	DirectoryUser bool     `json:"directory_user"`
+	LDAPServer    BaseType `json:"ldap_server"`
 }
 
 // Privileges models the Classic API's <privileges> block, which gives a
@@ -84,6 +91,58 @@ func (p *Privileges) Contains(privilege string) bool {
 		slices.Contains(p.CasperImaging, privilege)
 }
 
+// Privilege category element names, in the order the Classic API lists them.
+const (
+	PrivilegeCategoryJSSObjects    = "jss_objects"
+	PrivilegeCategoryJSSSettings   = "jss_settings"
+	PrivilegeCategoryJSSActions    = "jss_actions"
+	PrivilegeCategoryRecon         = "recon"
+	PrivilegeCategoryCasperAdmin   = "casper_admin"
+	PrivilegeCategoryCasperRemote  = "casper_remote"
+	PrivilegeCategoryCasperImaging = "casper_imaging"
+)
+
+type privilegeCategory struct {
+	name  string
+	items *[]string
+}
+
+// categories returns p's 7 categories in Classic API order, each pointing at
+// the backing field so callers can edit it in place.
+func (p *Privileges) categories() []privilegeCategory {
+	return []privilegeCategory{
+		{PrivilegeCategoryJSSObjects, &p.JSSObjects},
+		{PrivilegeCategoryJSSSettings, &p.JSSSettings},
+		{PrivilegeCategoryJSSActions, &p.JSSActions},
+		{PrivilegeCategoryRecon, &p.Recon},
+		{PrivilegeCategoryCasperAdmin, &p.CasperAdmin},
+		{PrivilegeCategoryCasperRemote, &p.CasperRemote},
+		{PrivilegeCategoryCasperImaging, &p.CasperImaging},
+	}
+}
+
+// Add appends privilege to the named category, unless it's already there.
+// Returns an error for an unknown category name.
+func (p *Privileges) Add(category string, privilege string) error {
+	for _, c := range p.categories() {
+		if c.name != category {
+			continue
+		}
+		if !slices.Contains(*c.items, privilege) {
+			*c.items = append(*c.items, privilege)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown privilege category %q", category)
+}
+
+// Remove deletes privilege from every category it appears in.
+func (p *Privileges) Remove(privilege string) {
+	for _, c := range p.categories() {
+		*c.items = slices.DeleteFunc(*c.items, func(item string) bool { return item == privilege })
+	}
+}
+
 // MarshalXML emits only the privilege categories that are populated.
 // encoding/xml's built-in "omitempty" does not apply to a nil/empty slice
 // nested behind a ">"-chained struct tag (e.g. "jss_objects>privilege") — it
@@ -93,28 +152,30 @@ func (p *Privileges) Contains(privilege string) bool {
 // "<jss_settings></jss_settings>". The struct field xml tags remain in place
 // for decoding (test-server's XML unmarshal still uses them).
 func (p Privileges) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalPrivileges(e, start, &p, false)
+}
+
+// ReplacementPrivileges is the <privileges> block of an account or group
+// update. Unlike Privileges it emits every category, empty ones included, so
+// a category emptied by a revoke is actually cleared on the Jamf side rather
+// than left untouched by an omitted element.
+type ReplacementPrivileges Privileges
+
+func (p ReplacementPrivileges) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalPrivileges(e, start, (*Privileges)(&p), true)
+}
+
+func marshalPrivileges(e *xml.Encoder, start xml.StartElement, p *Privileges, includeEmpty bool) error {
 	if err := e.EncodeToken(start); err != nil {
 		return err
 	}
-	categories := []struct {
-		name  string
-		items []string
-	}{
-		{"jss_objects", p.JSSObjects},
-		{"jss_settings", p.JSSSettings},
-		{"jss_actions", p.JSSActions},
-		{"recon", p.Recon},
-		{"casper_admin", p.CasperAdmin},
-		{"casper_remote", p.CasperRemote},
-		{"casper_imaging", p.CasperImaging},
-	}
-	for _, c := range categories {
-		if len(c.items) == 0 {
+	for _, c := range p.categories() {
+		if len(*c.items) == 0 && !includeEmpty {
 			continue
 		}
 		element := struct {
 			Items []string `xml:"privilege"`
-		}{Items: c.items}
+		}{Items: *c.items}
 		if err := e.EncodeElement(element, xml.StartElement{Name: xml.Name{Local: c.name}}); err != nil {
 			return err
 		}
@@ -138,6 +199,44 @@ type Group struct {
 	Privileges   Privileges `json:"privileges"`
 	Site         BaseType   `json:"site"`
 	Members      []BaseType `json:"members"`
+	// LDAPServer is set for a group that maps a directory group on an LDAP
+	// server; its members then come from that directory group.
+	LDAPServer BaseType `json:"ldap_server"`
+}
+
+// IsLDAP reports whether the account is backed by an LDAP server. Local
+// accounts report an ldap_server with ID -1 ("None"), or none at all.
+func (a *UserAccount) IsLDAP() bool {
+	return a.DirectoryUser || a.LDAPServer.ID > 0
+}
+
+// IsLDAP reports whether the group maps a directory group on an LDAP server.
+// Like accounts, local groups report an ldap_server with ID -1.
+func (g *Group) IsLDAP() bool {
+	return g.LDAPServer.ID > 0
+}
+
+// LDAPServer is an LDAP server Jamf authenticates accounts and groups
+// against.
+type LDAPServer struct {
+	Connection LDAPServerConnection `json:"connection"`
+}
+
+// LDAPServerConnection is the <connection> block of an LDAP server.
+type LDAPServerConnection struct {
+	BaseType
+	Hostname   string `json:"hostname"`
+	ServerType string `json:"server_type"`
+	Port       int    `json:"port"`
+	UseSSL     bool   `json:"use_ssl"`
+}
+
+type LDAPServersResponse struct {
+	LDAPServers []BaseType `json:"ldap_servers"`
+}
+
+type LDAPServerResponse struct {
+	LDAPServer LDAPServer `json:"ldap_server"`
 }
 
 type Site struct {
@@ -176,6 +275,23 @@ type TokenResponse struct {
 	Expires string `json:"expires"`
 }
 
+// bearerToken converts the response into a token issued at issuedAt. An
+// unparseable expiry falls back to defaultTokenLifetime.
+func (t TokenResponse) bearerToken(issuedAt time.Time) bearerToken {
+	var lifetime time.Duration
+	if expires, err := time.Parse(time.RFC3339, t.Expires); err == nil {
+		lifetime = expires.Sub(issuedAt)
+	}
+	return newBearerToken(t.Token, issuedAt, lifetime)
+}
+
+// OAuthTokenResponse is the response of POST /api/oauth/token.
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
 type UsersResponse struct {
 	Users []BaseType `json:"users"`
 }
@@ -184,6 +300,12 @@ type UserResponse struct {
 	User User `json:"user"`
 }
 
+// UsersByEmailResponse is the response of GET /JSSResource/users/email/{email},
+// which returns every user with that email address in full.
+type UsersByEmailResponse struct {
+	Users []User `json:"users"`
+}
+
 type UserAccountResponse struct {
 	UserAccount UserAccount `json:"account"`
 }
@@ -216,6 +338,105 @@ type UserAccountCreateBody struct {
 	Privileges *Privileges `xml:"privileges,omitempty"`
 }
 
+// UserAccountUpdateBody is the XML request body for PUT
+// /JSSResource/accounts/userid/{id}. The Classic API treats PUT as a partial
+// update, so only the elements that are set are changed on the account.
+type UserAccountUpdateBody struct {
+	XMLName      xml.Name `xml:"account"`
+	PrivilegeSet string   `xml:"privilege_set,omitempty"`
+	// Privileges replaces the account's custom privileges. Only set it
+	// together with PrivilegeSet — see GroupUpdateBody.Privileges.
+	Privileges *ReplacementPrivileges `xml:"privileges,omitempty"`
+	// Groups replaces the account's admin group memberships with the listed
+	// set. A pointer so the element is omitted entirely when group membership
+	// isn't being changed — a non-nil, empty AccountGroups clears them all.
+	Groups *AccountGroups `xml:"groups,omitempty"`
+	// Site moves the account to another site; NoSiteID takes it off its site.
+	Site *IDRef `xml:"site,omitempty"`
+	// Enabled is "Enabled" or "Disabled"; empty leaves it unchanged.
+	Enabled string `xml:"enabled,omitempty"`
+	// Password sets a local account's password; empty leaves it unchanged.
+	// Directory accounts have no Jamf password.
+	Password string `xml:"password,omitempty"`
+}
+
+// GroupUpdateBody is the XML request body for PUT
+// /JSSResource/accounts/groupid/{id}. Members are never sent: the group's
+// membership is managed through each account (see UserAccountUpdateBody.Groups).
+type GroupUpdateBody struct {
+	XMLName      xml.Name `xml:"group"`
+	PrivilegeSet string   `xml:"privilege_set,omitempty"`
+	// Privileges is a pointer so the whole <privileges> element is omitted
+	// when only PrivilegeSet changes; it's only meaningful for a Custom
+	// privilege set (see UserAccountCreateBody.Privileges).
+	Privileges *ReplacementPrivileges `xml:"privileges,omitempty"`
+	// Site moves the group to another site; NoSiteID takes it off its site.
+	Site *IDRef `xml:"site,omitempty"`
+}
+
+// AccountGroups is the <groups> block of an account update.
+type AccountGroups struct {
+	Groups []IDRef `xml:"group"`
+}
+
+// NewAccountGroups builds the <groups> block for the given group IDs.
+func NewAccountGroups(groupIDs []int) *AccountGroups {
+	groups := &AccountGroups{Groups: make([]IDRef, 0, len(groupIDs))}
+	for _, id := range groupIDs {
+		groups.Groups = append(groups.Groups, IDRef{ID: id})
+	}
+	return groups
+}
+
+// IDRef references an existing Jamf object by ID in an XML request body.
+type IDRef struct {
+	ID int `xml:"id"`
+}
+
+// NoSiteID is the site ID the Classic API uses for "None" — sending it
+// removes an object from its site.
+const NoSiteID = -1
+
+// UserUpdateBody is the XML request body for PUT /JSSResource/users/id/{id}.
+type UserUpdateBody struct {
+	XMLName xml.Name `xml:"user"`
+	// Sites replaces the user's site assignments with the listed set. A
+	// pointer for the same reason as UserAccountUpdateBody.Groups.
+	Sites *UserSites `xml:"sites,omitempty"`
+}
+
+// UserSites is the <sites> block of a user update.
+type UserSites struct {
+	Sites []IDRef `xml:"site"`
+}
+
+// NewUserSites builds the <sites> block for the given site IDs.
+func NewUserSites(siteIDs []int) *UserSites {
+	sites := &UserSites{Sites: make([]IDRef, 0, len(siteIDs))}
+	for _, id := range siteIDs {
+		sites.Sites = append(sites.Sites, IDRef{ID: id})
+	}
+	return sites
+}
+
+// UserGroupUpdateBody is the XML request body for PUT
+// /JSSResource/usergroups/id/{id}. user_additions and user_deletions change
+// a static group's membership incrementally, leaving every other member
+// untouched. Both are pointers because encoding/xml emits an empty wrapper
+// element for a nil slice behind a ">"-chained tag (see Privileges.MarshalXML).
+type UserGroupUpdateBody struct {
+	XMLName       xml.Name          `xml:"user_group"`
+	UserAdditions *UserGroupMembers `xml:"user_additions,omitempty"`
+	UserDeletions *UserGroupMembers `xml:"user_deletions,omitempty"`
+	// Site moves the group to another site; NoSiteID takes it off its site.
+	Site *IDRef `xml:"site,omitempty"`
+}
+
+// UserGroupMembers is a <user_additions>/<user_deletions> block.
+type UserGroupMembers struct {
+	Users []IDRef `xml:"user"`
+}
+
 type UserGroupsResponse struct {
 	UserGroups []UserGroup `json:"user_groups"`
 }
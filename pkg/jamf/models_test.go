@@ -82,3 +82,154 @@ func TestPrivileges_IsEmpty(t *testing.T) {
 		t.Error("expected a nil Privileges to be empty")
 	}
 }
+
+func TestUserAccountUpdateBody_Groups(t *testing.T) {
+	body := UserAccountUpdateBody{Groups: NewAccountGroups([]int{201, 203})}
+
+	out, err := xml.Marshal(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "<account><groups><group><id>201</id></group><group><id>203</id></group></groups></account>"
+	if string(out) != want {
+		t.Errorf("got %s, want %s", string(out), want)
+	}
+}
+
+func TestUserAccountUpdateBody_EmptyGroupsClearsMembership(t *testing.T) {
+	out, err := xml.Marshal(UserAccountUpdateBody{Groups: NewAccountGroups(nil)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(out), "<groups></groups>") {
+		t.Errorf("expected an empty <groups> element to clear memberships, got: %s", string(out))
+	}
+
+	out, err = xml.Marshal(UserAccountUpdateBody{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(out), "<groups") {
+		t.Errorf("expected no <groups> element when Groups is nil, got: %s", string(out))
+	}
+}
+
+func TestUserGroupUpdateBody_OmitsUnsetBlock(t *testing.T) {
+	body := UserGroupUpdateBody{UserAdditions: &UserGroupMembers{Users: []IDRef{{ID: 4}}}}
+
+	out, err := xml.Marshal(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "<user_group><user_additions><user><id>4</id></user></user_additions></user_group>"
+	if string(out) != want {
+		t.Errorf("got %s, want %s", string(out), want)
+	}
+}
+
+func TestComputerGroupUpdateBody_OmitsUnsetBlock(t *testing.T) {
+	body := ComputerGroupUpdateBody{ComputerDeletions: &ComputerGroupMembers{Computers: []IDRef{{ID: 7}}}}
+
+	out, err := xml.Marshal(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "<computer_group><computer_deletions><computer><id>7</id></computer></computer_deletions></computer_group>"
+	if string(out) != want {
+		t.Errorf("got %s, want %s", string(out), want)
+	}
+}
+
+func TestPrivileges_AddRemove(t *testing.T) {
+	var p Privileges
+	if err := p.Add(PrivilegeCategoryJSSObjects, "Read User"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Add(PrivilegeCategoryJSSObjects, "Read User"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(p.JSSObjects) != 1 {
+		t.Errorf("expected Add to skip a duplicate, got %v", p.JSSObjects)
+	}
+	if err := p.Add("jss_bogus", "Read User"); err == nil {
+		t.Error("expected an error for an unknown category")
+	}
+
+	p.Recon = []string{"Read User", "Update User"}
+	p.Remove("Read User")
+	if p.Contains("Read User") {
+		t.Errorf("expected Remove to clear every category, got %+v", p)
+	}
+	if !p.Contains("Update User") {
+		t.Errorf("expected Remove to leave other privileges alone, got %+v", p)
+	}
+}
+
+func TestGroupUpdateBody_ReplacementPrivilegesEmitsEmptyCategories(t *testing.T) {
+	body := GroupUpdateBody{
+		PrivilegeSet: "Custom",
+		Privileges:   &ReplacementPrivileges{Recon: []string{"Read User"}},
+	}
+
+	out, err := xml.Marshal(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := string(out)
+
+	if !strings.Contains(got, "<recon><privilege>Read User</privilege></recon>") {
+		t.Errorf("expected populated category, got: %s", got)
+	}
+	// An emptied category must still be sent, or Jamf leaves the old
+	// privileges in place.
+	for _, category := range []string{"jss_objects", "jss_settings", "jss_actions", "casper_admin", "casper_remote", "casper_imaging"} {
+		if !strings.Contains(got, "<"+category+"></"+category+">") {
+			t.Errorf("expected empty category %q to be emitted, got: %s", category, got)
+		}
+	}
+}
+
+func TestUserUpdateBody_EmptySitesClearsAssignments(t *testing.T) {
+	out, err := xml.Marshal(UserUpdateBody{Sites: NewUserSites(nil)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "<user><sites></sites></user>"
+	if string(out) != want {
+		t.Errorf("got %s, want %s", string(out), want)
+	}
+}
+
+func TestAppStoreID(t *testing.T) {
+	for url, want := range map[string]int{
+		"https://apps.apple.com/us/app/slack-for-desktop/id803453959?mt=12":   803453959,
+		"https://itunes.apple.com/us/app/microsoft-authenticator/id983156458": 983156458,
+		"": 0,
+		"https://downloads.example.com/inhouse.ipa": 0,
+	} {
+		if got := AppStoreID(url); got != want {
+			t.Errorf("AppStoreID(%q) = %d, want %d", url, got, want)
+		}
+	}
+}
+
+func TestIsLDAP_LocalSentinel(t *testing.T) {
+	none := BaseType{ID: -1, Name: "None"}
+	corpAD := BaseType{ID: 1, Name: "corp-ad"}
+
+	if (&UserAccount{LDAPServer: none}).IsLDAP() {
+		t.Error("expected an account on ldap_server -1 to be local")
+	}
+	if (&UserAccount{}).IsLDAP() {
+		t.Error("expected an account without an ldap_server to be local")
+	}
+	if !(&UserAccount{LDAPServer: corpAD}).IsLDAP() || !(&UserAccount{DirectoryUser: true, LDAPServer: none}).IsLDAP() {
+		t.Error("expected accounts on an LDAP server or marked as directory users to be LDAP")
+	}
+	if (&Group{LDAPServer: none}).IsLDAP() {
+		t.Error("expected a group on ldap_server -1 to be local")
+	}
+	if !(&Group{LDAPServer: corpAD}).IsLDAP() {
+		t.Error("expected a group on an LDAP server to be LDAP")
+	}
+}
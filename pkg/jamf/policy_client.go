@@ -0,0 +1,62 @@
+package jamf
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+const (
+	policyUrlPath   = "/JSSResource/policies/id/%d"
+	policiesUrlPath = "/JSSResource/policies"
+)
+
+// GetPolicyDetails returns the Jamf policy with the given ID, including its
+// scope. Requires the "Read Policies" privilege.
+func (c *Client) GetPolicyDetails(ctx context.Context, policyId int) (*Policy, error) {
+	url, err := c.getUrl(fmt.Sprintf(policyUrlPath, policyId))
+	if err != nil {
+		return nil, err
+	}
+
+	var target PolicyResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	return &target.Policy, nil
+}
+
+// GetPoliciesPage returns the details of up to limit Jamf policies starting
+// at offset, and the total number of policies. See GetUsersPage.
+func (c *Client) GetPoliciesPage(ctx context.Context, offset, limit int) ([]*Policy, int, error) {
+	ids, err := c.cache.policyIDs.get(ctx, c.getPolicyIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	policies, err := fetchDetails(ctx, c.concurrency, pageOf(ids, offset, limit), c.GetPolicyDetails)
+	if err != nil {
+		return nil, 0, err
+	}
+	return policies, len(ids), nil
+}
+
+func (c *Client) getPolicyIDs(ctx context.Context) ([]int, error) {
+	url, err := c.getUrl(policiesUrlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var target PoliciesResponse
+	if err := c.doRequest(ctx, url, &target); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(target.Policies))
+	for _, policy := range target.Policies {
+		ids = append(ids, policy.ID)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
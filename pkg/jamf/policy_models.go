@@ -0,0 +1,45 @@
+package jamf
+
+// ComputerScopeTargets are the targets a computer scope (or its exclusions)
+// lists. The same block appears on policies and macOS configuration
+// profiles.
+type ComputerScopeTargets struct {
+	Computers      []BaseType `json:"computers"`
+	ComputerGroups []BaseType `json:"computer_groups"`
+	Buildings      []BaseType `json:"buildings"`
+	Departments    []BaseType `json:"departments"`
+}
+
+// ComputerScope is the <scope> block of a policy or macOS configuration
+// profile. A computer is in scope when AllComputers is set or it matches one
+// of the targets, unless it matches one of the Exclusions.
+type ComputerScope struct {
+	AllComputers bool `json:"all_computers"`
+	ComputerScopeTargets
+	Exclusions ComputerScopeTargets `json:"exclusions"`
+}
+
+// Policy is a Jamf policy, which runs packages, scripts and other payloads
+// on the computers in its scope.
+type Policy struct {
+	General PolicyGeneral `json:"general"`
+	Scope   ComputerScope `json:"scope"`
+}
+
+// PolicyGeneral is the <general> block of a policy.
+type PolicyGeneral struct {
+	BaseType
+	Enabled   bool     `json:"enabled"`
+	Trigger   string   `json:"trigger"`
+	Frequency string   `json:"frequency"`
+	Category  BaseType `json:"category"`
+	Site      BaseType `json:"site"`
+}
+
+type PoliciesResponse struct {
+	Policies []BaseType `json:"policies"`
+}
+
+type PolicyResponse struct {
+	Policy Policy `json:"policy"`
+}
@@ -0,0 +1,88 @@
+package jamf
+
+import (
+	"context"
+	"sync"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// DefaultConcurrency is how many detail requests a Client runs at once when
+// SetConcurrency hasn't been called.
+const DefaultConcurrency = 5
+
+// fetchDetails calls fetch for every ID with at most concurrency calls in
+// flight, and returns the results in the order of ids. The first error
+// cancels the remaining calls and is returned. An ID whose detail request
+// comes back NotFound was deleted after it was listed, so it's skipped
+// rather than failing the whole fetch.
+func fetchDetails[T any](ctx context.Context, concurrency int, ids []int, fetch func(ctx context.Context, id int) (*T, error)) ([]*T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*T, len(ids))
+	jobs := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for range min(max(concurrency, 1), len(ids)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := fetch(ctx, ids[i])
+				if err != nil {
+					if IsNotFoundError(err) {
+						ctxzap.Extract(ctx).Debug("skipping object deleted since it was listed", zap.Int("id", ids[i]))
+						continue
+					}
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[i] = result
+			}
+		}()
+	}
+
+feed:
+	for i := range ids {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rv := make([]*T, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			rv = append(rv, result)
+		}
+	}
+	return rv, nil
+}
+
+// pageOf returns the page of at most limit items starting at offset. An
+// offset past the end yields an empty page.
+func pageOf[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	return items[max(offset, 0):min(max(offset, 0)+limit, len(items))]
+}
@@ -0,0 +1,112 @@
+package jamf
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFetchDetails_StableOrderAndBounded(t *testing.T) {
+	ids := make([]int, 40)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	var inFlight, peak atomic.Int32
+	got, err := fetchDetails(context.Background(), 4, ids, func(_ context.Context, id int) (*int, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		// Later IDs finish first, so completion order differs from input order.
+		time.Sleep(time.Duration(len(ids)-id) * 100 * time.Microsecond)
+		return &id, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gotIds := make([]int, 0, len(got))
+	for _, id := range got {
+		gotIds = append(gotIds, *id)
+	}
+	if !slices.Equal(gotIds, ids) {
+		t.Errorf("expected results in input order, got %v", gotIds)
+	}
+	if p := peak.Load(); p > 4 {
+		t.Errorf("expected at most 4 requests in flight, got %d", p)
+	}
+}
+
+func TestFetchDetails_SkipsNotFound(t *testing.T) {
+	got, err := fetchDetails(context.Background(), 2, []int{1, 2, 3}, func(_ context.Context, id int) (*int, error) {
+		if id == 2 {
+			return nil, status.Error(codes.NotFound, "gone")
+		}
+		return &id, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || *got[0] != 1 || *got[1] != 3 {
+		t.Errorf("expected [1 3], got %v", got)
+	}
+}
+
+func TestFetchDetails_CancelsOnFirstError(t *testing.T) {
+	ids := make([]int, 100)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	boom := errors.New("boom")
+
+	var calls atomic.Int32
+	_, err := fetchDetails(context.Background(), 2, ids, func(ctx context.Context, id int) (*int, error) {
+		calls.Add(1)
+		if id == 3 {
+			return nil, boom
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+		return &id, nil
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected the first error, got %v", err)
+	}
+	if n := calls.Load(); n > 10 {
+		t.Errorf("expected the remaining requests to be cancelled, but %d of %d ran", n, len(ids))
+	}
+}
+
+func TestPageOf(t *testing.T) {
+	ids := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		offset, limit int
+		want          []int
+	}{
+		{0, 2, []int{1, 2}},
+		{2, 2, []int{3, 4}},
+		{4, 2, []int{5}},
+		{5, 2, nil},
+		{9, 2, nil},
+	}
+	for _, tt := range tests {
+		if got := pageOf(ids, tt.offset, tt.limit); !slices.Equal(got, tt.want) {
+			t.Errorf("pageOf(%d, %d) = %v, want %v", tt.offset, tt.limit, got, tt.want)
+		}
+	}
+}
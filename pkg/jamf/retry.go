@@ -0,0 +1,155 @@
+package jamf
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultMaxAttempts is how many times a request is sent, counting the
+	// first, when SetRetryPolicy hasn't been called.
+	DefaultMaxAttempts = 5
+	// DefaultRetryBudget caps the total time a request spends being retried
+	// when SetRetryPolicy hasn't been called.
+	DefaultRetryBudget = 2 * time.Minute
+
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// RetryPolicy controls how requests rejected with 429 (rate limited) or a
+// transient 502/503/504 are retried. Only idempotent methods are retried; a
+// POST that failed may still have created something.
+type RetryPolicy struct {
+	// MaxAttempts is how many times a request is sent, counting the first.
+	// 1 disables retries.
+	MaxAttempts int
+	// Budget is how long after the first attempt a request may still be
+	// retried. A wait that would end past it isn't started.
+	Budget time.Duration
+
+	// baseDelay and maxDelay bound the exponential backoff used when Jamf
+	// doesn't send Retry-After. Zero means the defaults; tests shrink them.
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+// DefaultRetryPolicy returns the policy a Client uses until SetRetryPolicy is
+// called.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: DefaultMaxAttempts, Budget: DefaultRetryBudget}
+}
+
+// retrier tracks the retries of a single request.
+type retrier struct {
+	policy   RetryPolicy
+	attempt  int
+	deadline time.Time
+}
+
+func (p RetryPolicy) start(now time.Time) *retrier {
+	return &retrier{policy: p, attempt: 1, deadline: now.Add(p.Budget)}
+}
+
+// next reports whether the failed attempt should be retried and how long to
+// wait first. Jamf's Retry-After is honored when present; otherwise the wait
+// grows exponentially with jitter so concurrent workers don't retry in step.
+func (r *retrier) next(method string, response *http.Response, err error, now time.Time) (time.Duration, bool) {
+	if r.attempt >= r.policy.MaxAttempts || !isIdempotent(method) || !isRetryable(response, err) {
+		return 0, false
+	}
+
+	delay, ok := retryAfter(response, now)
+	if !ok {
+		delay = r.backoff()
+	}
+	if now.Add(delay).After(r.deadline) {
+		return 0, false
+	}
+
+	r.attempt++
+	return delay, true
+}
+
+// backoff returns a delay in [d/2, d), where d doubles with every attempt up
+// to maxDelay.
+func (r *retrier) backoff() time.Duration {
+	base, ceiling := r.policy.baseDelay, r.policy.maxDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	if ceiling <= 0 {
+		ceiling = defaultRetryMaxDelay
+	}
+
+	delay := ceiling
+	if shift := r.attempt - 1; shift < 32 && base<<shift < ceiling {
+		delay = base << shift
+	}
+	half := delay / 2
+	return half + rand.N(delay-half) //nolint:gosec // jitter, not security sensitive
+}
+
+// isIdempotent reports whether repeating a request with the given method is
+// safe. The Classic API's PUTs are partial updates to a fixed state, so
+// sending one twice has the same effect as sending it once.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryable reports whether a request failed for a reason that may clear up
+// on its own: rate limiting, an overloaded or restarting server, or a network
+// error that never got a response.
+func isRetryable(response *http.Response, err error) bool {
+	if response == nil {
+		return status.Code(err) == codes.Unavailable
+	}
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter parses a Retry-After header, given either as seconds or as an
+// HTTP date.
+func retryAfter(response *http.Response, now time.Time) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
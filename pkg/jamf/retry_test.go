@@ -0,0 +1,112 @@
+package jamf
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func responseWithStatus(code int, retryAfter string) *http.Response {
+	response := &http.Response{StatusCode: code, Header: http.Header{}}
+	if retryAfter != "" {
+		response.Header.Set("Retry-After", retryAfter)
+	}
+	return response
+}
+
+func TestRetrier_RetryableFailures(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+
+	tests := []struct {
+		name     string
+		method   string
+		response *http.Response
+		err      error
+		want     bool
+	}{
+		{"rate limited read", http.MethodGet, responseWithStatus(http.StatusTooManyRequests, ""), unavailable, true},
+		{"shed read", http.MethodGet, responseWithStatus(http.StatusServiceUnavailable, ""), unavailable, true},
+		{"shed put", http.MethodPut, responseWithStatus(http.StatusServiceUnavailable, ""), unavailable, true},
+		{"shed delete", http.MethodDelete, responseWithStatus(http.StatusBadGateway, ""), unavailable, true},
+		{"network error", http.MethodGet, nil, unavailable, true},
+		{"rate limited post", http.MethodPost, responseWithStatus(http.StatusTooManyRequests, ""), unavailable, false},
+		{"server error", http.MethodGet, responseWithStatus(http.StatusInternalServerError, ""), unavailable, false},
+		{"not found", http.MethodGet, responseWithStatus(http.StatusNotFound, ""), status.Error(codes.NotFound, "not found"), false},
+		{"other error", http.MethodGet, nil, errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := RetryPolicy{MaxAttempts: 3, Budget: time.Minute, baseDelay: time.Millisecond}
+			if _, got := policy.start(time.Now()).next(tt.method, tt.response, tt.err, time.Now()); got != tt.want {
+				t.Errorf("next() retry = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetrier_StopsAtMaxAttempts(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Budget: time.Minute, baseDelay: time.Millisecond}
+	r := policy.start(time.Now())
+	response := responseWithStatus(http.StatusTooManyRequests, "")
+
+	retries := 0
+	for {
+		if _, ok := r.next(http.MethodGet, response, nil, time.Now()); !ok {
+			break
+		}
+		retries++
+	}
+	if retries != 2 {
+		t.Errorf("expected 2 retries after the first attempt, got %d", retries)
+	}
+}
+
+func TestRetrier_HonorsRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := RetryPolicy{MaxAttempts: 5, Budget: time.Minute}
+
+	delay, ok := policy.start(now).next(http.MethodGet, responseWithStatus(http.StatusTooManyRequests, "7"), nil, now)
+	if !ok || delay != 7*time.Second {
+		t.Errorf("expected a 7s wait, got %v (retry %v)", delay, ok)
+	}
+
+	date := now.Add(20 * time.Second).Format(http.TimeFormat)
+	delay, ok = policy.start(now).next(http.MethodGet, responseWithStatus(http.StatusServiceUnavailable, date), nil, now)
+	if !ok || delay != 20*time.Second {
+		t.Errorf("expected a 20s wait, got %v (retry %v)", delay, ok)
+	}
+}
+
+func TestRetrier_RespectsBudget(t *testing.T) {
+	now := time.Now()
+	policy := RetryPolicy{MaxAttempts: 5, Budget: 10 * time.Second}
+
+	if _, ok := policy.start(now).next(http.MethodGet, responseWithStatus(http.StatusTooManyRequests, "30"), nil, now); ok {
+		t.Error("expected a wait past the budget not to be started")
+	}
+
+	r := policy.start(now)
+	if _, ok := r.next(http.MethodGet, responseWithStatus(http.StatusTooManyRequests, "1"), nil, now.Add(9500*time.Millisecond)); ok {
+		t.Error("expected no retry once the budget is nearly spent")
+	}
+}
+
+func TestRetrier_BackoffGrowsWithJitter(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, Budget: time.Hour, baseDelay: 100 * time.Millisecond, maxDelay: time.Second}
+	r := policy.start(time.Now())
+
+	for attempt, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		r.attempt = attempt + 1
+		want *= time.Millisecond
+		for range 20 {
+			if got := r.backoff(); got < want/2 || got >= want {
+				t.Fatalf("attempt %d: backoff %v outside [%v, %v)", r.attempt, got, want/2, want)
+			}
+		}
+	}
+}
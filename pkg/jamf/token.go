@@ -0,0 +1,104 @@
+package jamf
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultTokenLifetime is assumed when Jamf doesn't say when a token
+	// expires. It's well under Jamf's 30-minute default, so a wrong guess
+	// only costs an early refresh.
+	defaultTokenLifetime = 5 * time.Minute
+	// maxRefreshMargin is how long before expiry a token is refreshed.
+	// Tokens that live less than five times as long refresh once 80% of
+	// their lifetime has passed instead.
+	maxRefreshMargin = time.Minute
+)
+
+// bearerToken is a Jamf API token and the window it's accepted in.
+type bearerToken struct {
+	value     string
+	issuedAt  time.Time
+	expiresAt time.Time
+}
+
+func newBearerToken(value string, issuedAt time.Time, lifetime time.Duration) bearerToken {
+	if lifetime <= 0 {
+		lifetime = defaultTokenLifetime
+	}
+	return bearerToken{value: value, issuedAt: issuedAt, expiresAt: issuedAt.Add(lifetime)}
+}
+
+// needsRefresh reports whether the token is missing or close enough to its
+// expiry that it should be replaced before the next request.
+func (t bearerToken) needsRefresh(now time.Time) bool {
+	if t.value == "" {
+		return true
+	}
+	margin := min(maxRefreshMargin, t.expiresAt.Sub(t.issuedAt)/5)
+	return !now.Before(t.expiresAt.Add(-margin))
+}
+
+// tokenSource owns a Client's bearer token. It refreshes the token ahead of
+// its expiry and is safe to share across goroutines: callers that find the
+// token due at the same time wait for a single refresh rather than each
+// requesting their own.
+type tokenSource struct {
+	mu    sync.Mutex
+	token bearerToken
+
+	// acquire gets a brand new token from the client's credentials.
+	acquire func(ctx context.Context) (bearerToken, error)
+	// extend trades a still-valid token for one with a later expiry. Nil
+	// when the credentials don't support it (API clients).
+	extend func(ctx context.Context, token string) (bearerToken, error)
+}
+
+// Token returns a token that isn't due for a refresh, refreshing it first if
+// it is.
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if !s.token.needsRefresh(now) {
+		return s.token.value, nil
+	}
+
+	if s.extend != nil && s.token.value != "" && now.Before(s.token.expiresAt) {
+		token, err := s.extend(ctx, s.token.value)
+		if err == nil {
+			s.token = token
+			return token.value, nil
+		}
+		ctxzap.Extract(ctx).Debug("failed to extend token, acquiring a new one", zap.Error(err))
+	}
+
+	return s.acquireLocked(ctx)
+}
+
+// Reacquire replaces a token Jamf rejected. If another caller has already
+// replaced it, that newer token is returned instead of requesting another.
+func (s *tokenSource) Reacquire(ctx context.Context, rejected string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.value != "" && s.token.value != rejected {
+		return s.token.value, nil
+	}
+	return s.acquireLocked(ctx)
+}
+
+func (s *tokenSource) acquireLocked(ctx context.Context) (string, error) {
+	token, err := s.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	return token.value, nil
+}
@@ -6,7 +6,17 @@
 //   - PORT:          Server port (default: 8090, use 0 for a random port)
 //   - JAMF_USERNAME: Username the connector must authenticate with (default: "test-user")
 //   - JAMF_PASSWORD: Password the connector must authenticate with (default: "test-pass")
-//   - JAMF_TOKEN:    Bearer token minted by /api/v1/auth/token (default: "test-bearer-token")
+//   - JAMF_TOKEN:    Prefix of the bearer tokens minted by /api/v1/auth/token,
+//     /api/v1/auth/keep-alive and /api/oauth/token (default: "test-bearer-token")
+//   - JAMF_TOKEN_TTL: How long a minted token is accepted, as a Go duration
+//     (default: "1h"). Set it to a few seconds to exercise token refresh.
+//   - JAMF_CLIENT_ID:     API client ID accepted by /api/oauth/token (default: "test-client-id")
+//   - JAMF_CLIENT_SECRET: API client secret accepted by /api/oauth/token (default: "test-client-secret")
+//   - JAMF_FAULT_EVERY:  Fail every Nth API request (token endpoints excluded)
+//     with JAMF_FAULT_STATUS, to exercise the connector's retries (default: 0, off)
+//   - JAMF_FAULT_STATUS: Status code of injected failures, 429 or 503 (default: 429)
+//   - JAMF_FAULT_RETRY_AFTER: Retry-After value sent with injected failures, in
+//     seconds (default: unset, no header)
 //
 // JAMF_-prefixed (rather than bare USERNAME/PASSWORD/TOKEN) to avoid
 // colliding with ambient shell/system environment variables of the same name.
@@ -22,6 +32,13 @@
 //	  --password test-pass \
 //	  --instance-url http://localhost:8090
 //
+// or, as an API client:
+//
+//	./baton-jamf \
+//	  --jamf-client-id test-client-id \
+//	  --jamf-client-secret test-client-secret \
+//	  --instance-url http://localhost:8090
+//
 // Seeded data:
 //   - Sites: 1 "Headquarters", 2 "Remote"
 //   - Directory users (trait: user): john.appleseed (site 1), jane.doe,
@@ -30,14 +47,52 @@
 //   - Admin accounts (trait: user, resource type "userAccount"): admin1
 //     (Administrator, Enabled), admin2 (Auditor, Disabled — tests
 //     STATUS_DISABLED), admin3 (Custom, Enabled, carries a custom JSSObjects
-//     privilege).
+//     privilege), admin4 (Auditor, Enabled, a directory user on LDAP
+//     server 1).
 //   - Admin groups: group-admins (admin1+admin2), group-auditors
 //     (admin2+admin3 — admin2 overlaps two groups), group-custom (admin3,
-//     custom privilege).
-//   - User groups: usergroup-eng (john+jane), usergroup-sales (jane+dave —
-//     jane overlaps two groups), usergroup-empty (no members).
+//     custom privilege), group-directory-auditors (Auditor, maps a
+//     directory group on LDAP server 1, no Jamf-side members).
+//   - LDAP servers: 1 "corp-ad" (Active Directory). Local accounts and
+//     groups report ldap_server -1 "None", as the Classic API does.
+//   - User groups: usergroup-eng (john+jane), usergroup-sales (smart,
+//     jane+dave — jane overlaps two groups), usergroup-empty (no members).
 //   - Privileges (surfaced as custom roles): "Read Advanced Computer
 //     Searches", "Update Advanced Computer Searches", "Read User", "Update User".
+//   - API roles: "Device Auditors" (Read Advanced Computer Searches, Read
+//     User), "User Managers" (Read User, Update User).
+//   - API integrations: "Inventory Sync" (enabled, Device Auditors),
+//     "Onboarding Bot" (disabled — tests STATUS_DISABLED; Device Auditors and
+//     User Managers).
+//   - Computer groups: computergroup-all-macs (smart, computers 1+2),
+//     computergroup-finance (static, computer 2), computergroup-loaners
+//     (static, no members). Computer 3 is in no group, for granting.
+//   - Computers: 1 Johns-MacBook-Pro (john.appleseed, building 1,
+//     department 1), 2 Finance-iMac (jane.doe, building 1, department 2),
+//     3 Loaner-MacBook-Air (unassigned, building 2). Computers 1 and 2 have
+//     a local "itadmin" admin account; john.appleseed is a local admin on
+//     computer 1, jane.doe a standard local user on computer 2.
+//   - Mobile devices: 1 Johns-iPhone (john.appleseed, building 1,
+//     department 1), 2 Field-iPad-01 (field.tech, building 2).
+//   - Policies: 601 "Install Admin Tools" (computergroup-finance and
+//     computer 3, excluding computer 2), 602 "Security Baseline" (all
+//     computers, excluding building 2), 603 "Legacy Cleanup" (disabled,
+//     department 1).
+//   - Buildings: 1 "HQ Tower", 2 "Warehouse". Departments: 1 "Engineering",
+//     2 "Finance", 3 "Legal" (no devices).
+//   - Mac apps: 901 "Slack" (device-based VPP licenses, computergroup-all-macs
+//     excluding computer 2), 902 "Xcode" (usergroup-eng). Mobile device
+//     apps: 951 "Microsoft Authenticator" (all mobile devices).
+//   - VPP assignments: 1 "Engineering Licenses" (Xcode and Microsoft
+//     Authenticator, carol.smith).
+//   - macOS configuration profiles: 701 "Corporate VPN"
+//     (computergroup-all-macs, excluding computergroup-finance), 702 "Root
+//     Certificates" (all computers).
+//   - Mobile device configuration profiles: 801 "Corporate VPN (iOS)"
+//     (mobiledevicegroup-all-iphones, excluding mobile device 2), 802 "Wi-Fi
+//   - Warehouse" (building 2).
+//   - Mobile device groups: mobiledevicegroup-all-iphones (smart, mobile
+//     devices 1+2), mobiledevicegroup-field-ipads (static, mobile device 2).
 //
 // The mock enforces the Classic API's documented content-type contract: GET
 // responses are JSON, but POST/PUT request bodies must be XML — a JSON POST
@@ -45,9 +100,9 @@
 // https://developer.jamf.com/jamf-pro/docs/getting-started-2). This is what
 // caught baton-jamf's CreateAccount originally sending JSON bodies.
 //
-// managedDevice (computers / mobile devices) is opt-in in the connector and
-// is NOT mocked here — it targets separate v1 inventory endpoints outside
-// the scope of this test server. Do not select it against this mock.
+// managedDevice (computers / mobile devices) is opt-in in the connector. The
+// computers inventory and mobile device list are mocked with only the fields
+// the connector reads, enough to sync devices and resolve policy scopes.
 package main
 
 import (
@@ -73,15 +128,21 @@ const (
 	defaultUsername = "test-user"
 	defaultPassword = "test-pass"
 	defaultToken    = "test-bearer-token"
+	defaultTokenTTL = time.Hour
+
+	defaultClientID     = "test-client-id"
+	defaultClientSecret = "test-client-secret"
 
 	siteNameHeadquarters = "Headquarters"
 	siteNameRemote       = "Remote"
 
 	accessLevelFullAccess     = "Full Access"
+	accessLevelSiteAccess     = "Site Access"
 	privilegeSetAdministrator = "Administrator"
 	privilegeSetAuditor       = "Auditor"
 	privilegeSetCustom        = "Custom"
 	enabledValue              = "Enabled"
+	disabledValue             = "Disabled"
 
 	privilegeReadAdvancedComputerSearches = "Read Advanced Computer Searches"
 )
@@ -90,17 +151,39 @@ const (
 // https://developer.jamf.com/jamf-pro/reference/createaccountbyid and
 // https://developer.jamf.com/jamf-pro/reference/findaccountsbyid.
 var (
-	validAccessLevels  = []string{"Full Access", "Site Access", "Group Access"}
+	validAccessLevels  = []string{accessLevelFullAccess, accessLevelSiteAccess, "Group Access"}
 	validPrivilegeSets = []string{privilegeSetAdministrator, privilegeSetAuditor, "Enrollment Only", privilegeSetCustom}
-	validEnabledValues = []string{enabledValue, "Disabled"}
+	validEnabledValues = []string{enabledValue, disabledValue}
 )
 
+// noLDAPServer is the ldap_server the Classic API reports for local accounts
+// and groups.
+var noLDAPServer = jamf.BaseType{ID: -1, Name: "None"}
+
 type server struct {
 	mu sync.Mutex
 
-	username string
-	password string
-	token    string
+	username     string
+	password     string
+	clientID     string
+	clientSecret string
+
+	// Every token endpoint mints a fresh "<tokenPrefix>-<n>" token that's
+	// accepted until tokenTTL passes. keep-alive retires the token it was
+	// called with, like the real API.
+	tokenPrefix    string
+	tokenTTL       time.Duration
+	tokens         map[string]time.Time
+	tokensIssued   int
+	tokensRejected int
+
+	// Every faultEvery-th API request is answered with faultStatus (and
+	// faultRetryAfter as Retry-After, when set) instead of being served.
+	faultEvery      int
+	faultStatus     int
+	faultRetryAfter string
+	apiRequests     int
+	faultsInjected  int
 
 	users      map[int]*jamf.User
 	userList   []*jamf.User
@@ -109,6 +192,9 @@ type server struct {
 	accounts      map[int]*jamf.UserAccount
 	accountList   []*jamf.UserAccount
 	nextAccountID int
+	// accountPasswords holds the passwords set on local accounts through the
+	// API, by account ID. Like Jamf, the mock never returns them.
+	accountPasswords map[int]string
 
 	groups    map[int]*jamf.Group
 	groupList []*jamf.Group
@@ -118,17 +204,61 @@ type server struct {
 
 	sites      []jamf.Site
 	privileges []string
+
+	apiRoles        []jamf.APIRole
+	apiIntegrations []jamf.APIIntegration
+
+	ldapServers []jamf.LDAPServer
+
+	// computers are the computer group member records of computerInventory.
+	computers         map[int]jamf.ComputerGroupMember
+	computerInventory []jamf.ComputerInventory
+	mobileDevices     []jamf.MobileDevice
+	// mobileDeviceDetails holds the USER_AND_LOCATION section of mobileDevices.
+	mobileDeviceDetails []jamf.MobileDeviceDetail
+	computerGroups      map[int]*jamf.ComputerGroup
+	computerGroupList   []*jamf.ComputerGroup
+
+	mobileDeviceGroups    map[int]*jamf.MobileDeviceGroup
+	mobileDeviceGroupList []*jamf.MobileDeviceGroup
+
+	policies   map[int]*jamf.Policy
+	policyList []*jamf.Policy
+
+	buildings   []jamf.Building
+	departments []jamf.Department
+
+	macApplications          []*jamf.MacApplication
+	mobileDeviceApplications []*jamf.MobileDeviceApplication
+	vppAssignments           []*jamf.VPPAssignment
+
+	// mdmCommands records every MDM command sent, in order.
+	mdmCommands []mdmCommand
+
+	macOSConfigurationProfiles        []*jamf.MacOSConfigurationProfile
+	mobileDeviceConfigurationProfiles []*jamf.MobileDeviceConfigurationProfile
 }
 
-func newServer(username, password, token string) *server {
+func newServer(username, password, clientID, clientSecret, token string) *server {
 	s := &server{
-		username:   username,
-		password:   password,
-		token:      token,
-		users:      make(map[int]*jamf.User),
-		accounts:   make(map[int]*jamf.UserAccount),
-		groups:     make(map[int]*jamf.Group),
-		userGroups: make(map[int]*jamf.UserGroup),
+		username:         username,
+		password:         password,
+		clientID:         clientID,
+		clientSecret:     clientSecret,
+		tokenPrefix:      token,
+		tokenTTL:         defaultTokenTTL,
+		tokens:           make(map[string]time.Time),
+		faultStatus:      http.StatusTooManyRequests,
+		users:            make(map[int]*jamf.User),
+		accounts:         make(map[int]*jamf.UserAccount),
+		accountPasswords: make(map[int]string),
+		groups:           make(map[int]*jamf.Group),
+		userGroups:       make(map[int]*jamf.UserGroup),
+
+		computers:          make(map[int]jamf.ComputerGroupMember),
+		policies:           make(map[int]*jamf.Policy),
+		computerGroups:     make(map[int]*jamf.ComputerGroup),
+		mobileDeviceGroups: make(map[int]*jamf.MobileDeviceGroup),
 	}
 	s.seedData()
 	return s
@@ -173,17 +303,29 @@ func (s *server) seedData() {
 	admin1 := &jamf.UserAccount{
 		BaseType: jamf.BaseType{ID: 101, Name: "admin1"}, FullName: "Admin One", Email: "admin1@example.com",
 		Enabled: enabledValue, AccessLevel: accessLevelFullAccess, PrivilegeSet: privilegeSetAdministrator, Site: headquarters,
+		LDAPServer: noLDAPServer,
 	}
 	admin2 := &jamf.UserAccount{
 		BaseType: jamf.BaseType{ID: 102, Name: "admin2"}, FullName: "Admin Two", Email: "admin2@example.com",
-		Enabled: "Disabled", AccessLevel: accessLevelFullAccess, PrivilegeSet: privilegeSetAuditor, Site: headquarters,
+		Enabled: disabledValue, AccessLevel: accessLevelFullAccess, PrivilegeSet: privilegeSetAuditor, Site: headquarters,
+		LDAPServer: noLDAPServer,
 	}
 	admin3 := &jamf.UserAccount{
 		BaseType: jamf.BaseType{ID: 103, Name: "admin3"}, FullName: "Admin Three", Email: "admin3@example.com",
 		Enabled: enabledValue, AccessLevel: accessLevelFullAccess, PrivilegeSet: privilegeSetCustom, Site: remote,
 		Privileges: jamf.Privileges{JSSObjects: []string{privilegeReadAdvancedComputerSearches}},
-	}
-	accounts := []*jamf.UserAccount{admin1, admin2, admin3}
+		LDAPServer: noLDAPServer,
+	}
+	corpAD := jamf.BaseType{ID: 1, Name: "corp-ad"}
+	s.ldapServers = []jamf.LDAPServer{{Connection: jamf.LDAPServerConnection{
+		BaseType: corpAD, Hostname: "ad.example.com", ServerType: "Active Directory", Port: 636, UseSSL: true,
+	}}}
+	admin4 := &jamf.UserAccount{
+		BaseType: jamf.BaseType{ID: 104, Name: "admin4"}, FullName: "Admin Four", Email: "admin4@example.com",
+		Enabled: enabledValue, AccessLevel: accessLevelFullAccess, PrivilegeSet: privilegeSetAuditor, Site: headquarters,
+		DirectoryUser: true, LDAPServer: corpAD,
+	}
+	accounts := []*jamf.UserAccount{admin1, admin2, admin3, admin4}
 	for _, a := range accounts {
 		s.accounts[a.ID] = a
 		s.accountList = append(s.accountList, a)
@@ -197,16 +339,21 @@ func (s *server) seedData() {
 	groups := []*jamf.Group{
 		{
 			BaseType: jamf.BaseType{ID: 201, Name: "group-admins"}, AccessLevel: accessLevelFullAccess, PrivilegeSet: privilegeSetAdministrator, Site: headquarters,
-			Members: []jamf.BaseType{admin1Ref, admin2Ref},
+			Members: []jamf.BaseType{admin1Ref, admin2Ref}, LDAPServer: noLDAPServer,
 		},
 		{
 			BaseType: jamf.BaseType{ID: 202, Name: "group-auditors"}, AccessLevel: accessLevelFullAccess, PrivilegeSet: privilegeSetAuditor, Site: headquarters,
-			Members: []jamf.BaseType{admin2Ref, admin3Ref},
+			Members: []jamf.BaseType{admin2Ref, admin3Ref}, LDAPServer: noLDAPServer,
 		},
 		{
 			BaseType: jamf.BaseType{ID: 203, Name: "group-custom"}, AccessLevel: accessLevelFullAccess, PrivilegeSet: privilegeSetCustom, Site: remote,
 			Privileges: jamf.Privileges{JSSObjects: []string{privilegeReadAdvancedComputerSearches}},
 			Members:    []jamf.BaseType{admin3Ref},
+			LDAPServer: noLDAPServer,
+		},
+		{
+			BaseType: jamf.BaseType{ID: 204, Name: "group-directory-auditors"}, AccessLevel: accessLevelFullAccess, PrivilegeSet: privilegeSetAuditor, Site: headquarters,
+			LDAPServer: corpAD,
 		},
 	}
 	for _, g := range groups {
@@ -238,6 +385,207 @@ func (s *server) seedData() {
 		"Read User",
 		"Update User",
 	}
+
+	s.apiRoles = []jamf.APIRole{
+		{ID: "1", DisplayName: "Device Auditors", Privileges: []string{privilegeReadAdvancedComputerSearches, "Read User"}},
+		{ID: "2", DisplayName: "User Managers", Privileges: []string{"Read User", "Update User"}},
+	}
+	s.apiIntegrations = []jamf.APIIntegration{
+		{
+			ID: 1, DisplayName: "Inventory Sync", Enabled: true, AuthorizationScopes: []string{"Device Auditors"},
+			AccessTokenLifetimeSeconds: 300, AppType: "CLIENT_CREDENTIALS", ClientID: "inventory-sync-client",
+		},
+		{
+			ID: 2, DisplayName: "Onboarding Bot", AuthorizationScopes: []string{"Device Auditors", "User Managers"},
+			AccessTokenLifetimeSeconds: 1800, AppType: "CLIENT_CREDENTIALS", ClientID: "onboarding-bot-client",
+		},
+	}
+
+	computer1 := jamf.ComputerGroupMember{BaseType: jamf.BaseType{ID: 1, Name: "Johns-MacBook-Pro"}, SerialNumber: "C02XK1JHJG5H"}
+	computer2 := jamf.ComputerGroupMember{BaseType: jamf.BaseType{ID: 2, Name: "Finance-iMac"}, SerialNumber: "C02YL2KJJV40"}
+	computer3 := jamf.ComputerGroupMember{BaseType: jamf.BaseType{ID: 3, Name: "Loaner-MacBook-Air"}, SerialNumber: "FVFXK3LMHV2D"}
+	for _, c := range []jamf.ComputerGroupMember{computer1, computer2, computer3} {
+		s.computers[c.ID] = c
+	}
+	s.computerInventory = []jamf.ComputerInventory{
+		mockComputer(computer1, "MacBook Pro (16-inch, 2021)", "MacBookPro18,1", &jamf.ComputerUserAndLocation{
+			Username: "john.appleseed", Email: "john.appleseed@example.com", BuildingID: "1", DepartmentID: "1",
+		}, mockLocalAccount(501, "john.appleseed", "John Appleseed", true), mockLocalAccount(502, "itadmin", "IT Admin", true)),
+		mockComputer(computer2, "iMac (24-inch, M1, 2021)", "iMac21,1", &jamf.ComputerUserAndLocation{
+			Username: "jane.doe", Email: "jane.doe@example.com", BuildingID: "1", DepartmentID: "2",
+		}, mockLocalAccount(501, "jane.doe", "Jane Doe", false), mockLocalAccount(502, "itadmin", "IT Admin", true)),
+		mockComputer(computer3, "MacBook Air (M2, 2022)", "Mac14,2", &jamf.ComputerUserAndLocation{BuildingID: "2"}),
+	}
+	computerGroups := []*jamf.ComputerGroup{
+		{
+			BaseType: jamf.BaseType{ID: 401, Name: "computergroup-all-macs"}, IsSmart: true,
+			Computers: []jamf.ComputerGroupMember{computer1, computer2},
+		},
+		{
+			BaseType: jamf.BaseType{ID: 402, Name: "computergroup-finance"}, Site: headquarters,
+			Computers: []jamf.ComputerGroupMember{computer2},
+		},
+		{
+			BaseType: jamf.BaseType{ID: 403, Name: "computergroup-loaners"},
+		},
+	}
+	for _, cg := range computerGroups {
+		s.computerGroups[cg.ID] = cg
+		s.computerGroupList = append(s.computerGroupList, cg)
+	}
+
+	mobile1 := jamf.MobileDeviceGroupMember{BaseType: jamf.BaseType{ID: 1, Name: "Johns-iPhone"}, SerialNumber: "F2LXK1JHJC67", UDID: "00008030-001A2B3C4D5E6F70"}
+	mobile2 := jamf.MobileDeviceGroupMember{BaseType: jamf.BaseType{ID: 2, Name: "Field-iPad-01"}, SerialNumber: "DMPYL2KJJF8J", UDID: "00008027-000B1C2D3E4F5A6B"}
+	mobileDeviceGroups := []*jamf.MobileDeviceGroup{
+		{
+			BaseType: jamf.BaseType{ID: 501, Name: "mobiledevicegroup-all-iphones"}, IsSmart: true,
+			MobileDevices: []jamf.MobileDeviceGroupMember{mobile1, mobile2},
+		},
+		{
+			BaseType: jamf.BaseType{ID: 502, Name: "mobiledevicegroup-field-ipads"}, Site: remote,
+			MobileDevices: []jamf.MobileDeviceGroupMember{mobile2},
+		},
+	}
+	for _, mg := range mobileDeviceGroups {
+		s.mobileDeviceGroups[mg.ID] = mg
+		s.mobileDeviceGroupList = append(s.mobileDeviceGroupList, mg)
+	}
+	s.mobileDevices = []jamf.MobileDevice{
+		mockMobileDevice(mobile1, "iPhone 14", "iPhone14,7", "john.appleseed"),
+		mockMobileDevice(mobile2, "iPad Air (5th generation)", "iPad13,16", "field.tech"),
+	}
+	s.mobileDeviceDetails = []jamf.MobileDeviceDetail{
+		{MobileDeviceID: "1", UserAndLocation: &jamf.MobileDeviceUserAndLocation{
+			Username: "john.appleseed", EmailAddress: "john.appleseed@example.com", BuildingID: "1", DepartmentID: "1",
+		}},
+		{MobileDeviceID: "2", UserAndLocation: &jamf.MobileDeviceUserAndLocation{Username: "field.tech", BuildingID: "2"}},
+	}
+
+	policies := []*jamf.Policy{
+		{
+			General: jamf.PolicyGeneral{BaseType: jamf.BaseType{ID: 601, Name: "Install Admin Tools"}, Enabled: true, Trigger: "EVENT", Frequency: "Once per computer"},
+			Scope: jamf.ComputerScope{
+				ComputerScopeTargets: jamf.ComputerScopeTargets{
+					Computers:      []jamf.BaseType{computer3.BaseType},
+					ComputerGroups: []jamf.BaseType{{ID: 402, Name: "computergroup-finance"}},
+				},
+				Exclusions: jamf.ComputerScopeTargets{Computers: []jamf.BaseType{computer2.BaseType}},
+			},
+		},
+		{
+			General: jamf.PolicyGeneral{BaseType: jamf.BaseType{ID: 602, Name: "Security Baseline"}, Enabled: true, Trigger: "CHECKIN", Frequency: "Ongoing", Site: headquarters},
+			Scope: jamf.ComputerScope{
+				AllComputers: true,
+				Exclusions:   jamf.ComputerScopeTargets{Buildings: []jamf.BaseType{{ID: 2, Name: "Warehouse"}}},
+			},
+		},
+		{
+			General: jamf.PolicyGeneral{BaseType: jamf.BaseType{ID: 603, Name: "Legacy Cleanup"}, Trigger: "CHECKIN", Frequency: "Once per computer"},
+			Scope: jamf.ComputerScope{
+				ComputerScopeTargets: jamf.ComputerScopeTargets{Departments: []jamf.BaseType{{ID: 1, Name: "Engineering"}}},
+			},
+		},
+	}
+	for _, p := range policies {
+		s.policies[p.General.ID] = p
+		s.policyList = append(s.policyList, p)
+	}
+
+	s.buildings = []jamf.Building{
+		{ID: "1", Name: "HQ Tower", StreetAddress1: "1 Infinite Loop", City: "Cupertino", StateProvince: "CA", ZipPostalCode: "95014", Country: "US"},
+		{ID: "2", Name: "Warehouse", City: "Minneapolis", StateProvince: "MN", Country: "US"},
+	}
+	s.departments = []jamf.Department{
+		{ID: "1", Name: "Engineering"},
+		{ID: "2", Name: "Finance"},
+		{ID: "3", Name: "Legal"},
+	}
+
+	s.macApplications = []*jamf.MacApplication{
+		{
+			General: jamf.MacApplicationGeneral{
+				BaseType: jamf.BaseType{ID: 901, Name: "Slack"}, Version: "4.38.125", BundleID: "com.tinyspeck.slackmacgap",
+				URL: "https://apps.apple.com/us/app/slack-for-desktop/id803453959?mt=12", IsFree: true, DeploymentType: "Install Automatically/Prompt Users to Install",
+			},
+			Scope: jamf.MacApplicationScope{
+				ComputerScopeTargets: jamf.ComputerScopeTargets{ComputerGroups: []jamf.BaseType{{ID: 401, Name: "computergroup-all-macs"}}},
+				Exclusions:           jamf.MacApplicationScopeExclusions{ComputerScopeTargets: jamf.ComputerScopeTargets{Computers: []jamf.BaseType{computer2.BaseType}}},
+			},
+			VPP: jamf.AppVPP{AssignVPPDeviceBasedLicenses: true, VPPAdminAccountID: 1},
+		},
+		{
+			General: jamf.MacApplicationGeneral{
+				BaseType: jamf.BaseType{ID: 902, Name: "Xcode"}, Version: "15.3", BundleID: "com.apple.dt.Xcode",
+				URL: "https://apps.apple.com/us/app/xcode/id497799835?mt=12", IsFree: true, DeploymentType: "Make Available in Self Service", Site: headquarters,
+			},
+			Scope: jamf.MacApplicationScope{
+				UserScopeTargets: jamf.UserScopeTargets{JSSUserGroups: []jamf.BaseType{{ID: 301, Name: "usergroup-eng"}}},
+			},
+			VPP: jamf.AppVPP{VPPAdminAccountID: 1},
+		},
+	}
+	s.mobileDeviceApplications = []*jamf.MobileDeviceApplication{
+		{
+			General: jamf.MobileDeviceApplicationGeneral{
+				BaseType: jamf.BaseType{ID: 951, Name: "Microsoft Authenticator"}, DisplayName: "Authenticator", Version: "6.8.7",
+				BundleID: "com.microsoft.azureauthenticator", ITunesStoreURL: "https://apps.apple.com/us/app/microsoft-authenticator/id983156458?uo=4",
+				Free: true, DeploymentType: "Install Automatically/Prompt Users to Install",
+			},
+			Scope: jamf.MobileDeviceApplicationScope{AllMobileDevices: true},
+			VPP:   jamf.AppVPP{AssignVPPDeviceBasedLicenses: true, VPPAdminAccountID: 1},
+		},
+	}
+	s.vppAssignments = []*jamf.VPPAssignment{
+		{
+			General: jamf.VPPAssignmentGeneral{BaseType: jamf.BaseType{ID: 1, Name: "Engineering Licenses"}, VPPAdminAccountID: 1, VPPAdminAccountName: "Example Corp VPP"},
+			MacApps: []jamf.VPPApp{{AdamID: 497799835, Name: "Xcode"}},
+			IOSApps: []jamf.VPPApp{{AdamID: 983156458, Name: "Microsoft Authenticator"}},
+			Scope: jamf.VPPAssignmentScope{
+				UserScopeTargets: jamf.UserScopeTargets{JSSUsers: []jamf.BaseType{{ID: 3, Name: "carol.smith"}}},
+			},
+		},
+	}
+
+	s.macOSConfigurationProfiles = []*jamf.MacOSConfigurationProfile{
+		{
+			General: jamf.ConfigurationProfileGeneral{
+				BaseType: jamf.BaseType{ID: 701, Name: "Corporate VPN"}, Description: "Always-on VPN for managed Macs",
+				Level: "System", DistributionMethod: "Install Automatically", UUID: "0D5A8C51-7A0B-4E1B-9B2B-6C3E1F0A7010",
+			},
+			Scope: jamf.ComputerScope{
+				ComputerScopeTargets: jamf.ComputerScopeTargets{ComputerGroups: []jamf.BaseType{{ID: 401, Name: "computergroup-all-macs"}}},
+				Exclusions:           jamf.ComputerScopeTargets{ComputerGroups: []jamf.BaseType{{ID: 402, Name: "computergroup-finance"}}},
+			},
+		},
+		{
+			General: jamf.ConfigurationProfileGeneral{
+				BaseType: jamf.BaseType{ID: 702, Name: "Root Certificates"}, Level: "Computer",
+				DistributionMethod: "Install Automatically", UUID: "6B1F3E22-94C1-4F57-8C07-2B9D8E4A7020", Site: headquarters,
+			},
+			Scope: jamf.ComputerScope{AllComputers: true},
+		},
+	}
+	s.mobileDeviceConfigurationProfiles = []*jamf.MobileDeviceConfigurationProfile{
+		{
+			General: jamf.ConfigurationProfileGeneral{
+				BaseType:           jamf.BaseType{ID: 801, Name: "Corporate VPN (iOS)"},
+				DistributionMethod: "Install Automatically", UUID: "A3C47D10-55E2-4B8A-9F61-0E2D4C6B8010",
+			},
+			Scope: jamf.MobileDeviceScope{
+				MobileDeviceScopeTargets: jamf.MobileDeviceScopeTargets{MobileDeviceGroups: []jamf.BaseType{{ID: 501, Name: "mobiledevicegroup-all-iphones"}}},
+				Exclusions:               jamf.MobileDeviceScopeTargets{MobileDevices: []jamf.BaseType{mobile2.BaseType}},
+			},
+		},
+		{
+			General: jamf.ConfigurationProfileGeneral{
+				BaseType:           jamf.BaseType{ID: 802, Name: "Wi-Fi - Warehouse"},
+				DistributionMethod: "Install Automatically", UUID: "F19B2E84-3D6C-4A0E-B7F5-8C1A2D3E8020", Site: remote,
+			},
+			Scope: jamf.MobileDeviceScope{
+				MobileDeviceScopeTargets: jamf.MobileDeviceScopeTargets{Buildings: []jamf.BaseType{{ID: 2, Name: "Warehouse"}}},
+			},
+		},
+	}
 }
 
 // ── Auth ─────────────────────────────────────────────────────────────────────
@@ -254,9 +602,36 @@ func (s *server) handleCreateToken(w http.ResponseWriter, r *http.Request) {
 		writeJSONError(w, http.StatusUnauthorized, "invalid credentials")
 		return
 	}
+	token, expires := s.mintToken()
 	writeJSON(w, http.StatusOK, jamf.TokenResponse{
-		Token:   s.token,
-		Expires: time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		Token:   token,
+		Expires: expires.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/post_oauth-token
+func (s *server) handleCreateOAuthToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be POST")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid form body")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "client_credentials" {
+		writeJSONError(w, http.StatusBadRequest, "unsupported grant_type")
+		return
+	}
+	if r.PostForm.Get("client_id") != s.clientID || r.PostForm.Get("client_secret") != s.clientSecret {
+		writeJSONError(w, http.StatusUnauthorized, "invalid client credentials")
+		return
+	}
+	token, _ := s.mintToken()
+	writeJSON(w, http.StatusOK, jamf.OAuthTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.tokenTTL / time.Second),
 	})
 }
 
@@ -269,9 +644,13 @@ func (s *server) handleKeepAlive(w http.ResponseWriter, r *http.Request) {
 		writeJSONError(w, http.StatusMethodNotAllowed, "method must be POST")
 		return
 	}
+	s.mu.Lock()
+	delete(s.tokens, bearerToken(r))
+	s.mu.Unlock()
+	token, expires := s.mintToken()
 	writeJSON(w, http.StatusOK, jamf.TokenResponse{
-		Token:   s.token,
-		Expires: time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		Token:   token,
+		Expires: expires.UTC().Format(time.RFC3339Nano),
 	})
 }
 
@@ -297,13 +676,64 @@ func (s *server) handleTokenDetails(w http.ResponseWriter, r *http.Request) {
 // the response already sent) when it's missing or wrong. Returns true when
 // the caller should proceed.
 func (s *server) requireBearer(w http.ResponseWriter, r *http.Request) bool {
-	if r.Header.Get("Authorization") != "Bearer "+s.token {
-		writeJSONError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+	s.mu.Lock()
+	expires, ok := s.tokens[bearerToken(r)]
+	valid := ok && time.Now().Before(expires)
+	if !valid {
+		s.tokensRejected++
+	}
+	s.mu.Unlock()
+	if !valid {
+		writeJSONError(w, http.StatusUnauthorized, "missing, invalid or expired bearer token")
 		return false
 	}
 	return true
 }
 
+// mintToken issues a new bearer token and returns it with its expiry.
+func (s *server) mintToken() (string, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokensIssued++
+	token := fmt.Sprintf("%s-%d", s.tokenPrefix, s.tokensIssued)
+	expires := time.Now().Add(s.tokenTTL)
+	s.tokens[token] = expires
+	return token, expires
+}
+
+func bearerToken(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// injectFaults fails every faultEvery-th API request with faultStatus, the
+// way Jamf Cloud sheds load. Token endpoints are never failed.
+func (s *server) injectFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/v1/auth") || r.URL.Path == "/api/oauth/token" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		s.mu.Lock()
+		s.apiRequests++
+		fail := s.faultEvery > 0 && s.apiRequests%s.faultEvery == 0
+		if fail {
+			s.faultsInjected++
+		}
+		status, retryAfter := s.faultStatus, s.faultRetryAfter
+		s.mu.Unlock()
+
+		if !fail {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		writeJSONError(w, status, "injected fault")
+	})
+}
+
 // ── Directory users (/JSSResource/users) ────────────────────────────────────
 
 // Doc URL: https://developer.jamf.com/jamf-pro/reference/findusers
@@ -325,11 +755,12 @@ func (s *server) handleListUsers(w http.ResponseWriter, r *http.Request) {
 }
 
 // handleUserByID dispatches GET (by numeric ID) / POST (create, ID must be
-// 0) / DELETE (by numeric ID) on /JSSResource/users/id/{id}.
+// 0) / PUT (update sites) / DELETE (by numeric ID) on /JSSResource/users/id/{id}.
 //
 // Doc URLs:
 //   - https://developer.jamf.com/jamf-pro/reference/finduserbyid
 //   - https://developer.jamf.com/jamf-pro/reference/createuserbyid
+//   - https://developer.jamf.com/jamf-pro/reference/updateuserbyid
 //   - https://developer.jamf.com/jamf-pro/reference/deleteuserbyid
 func (s *server) handleUserByID(w http.ResponseWriter, r *http.Request) {
 	if !s.requireBearer(w, r) {
@@ -393,6 +824,42 @@ func (s *server) handleUserByID(w http.ResponseWriter, r *http.Request) {
 		// see https://developer.jamf.com/jamf-pro/reference/createuserbyid.
 		writeJSON(w, http.StatusCreated, createResponse{ID: id})
 
+	case http.MethodPut:
+		body, ok := decodeXMLBody[jamf.UserUpdateBody](w, r)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		u, ok := s.users[id]
+		if !ok {
+			s.mu.Unlock()
+			writeJSONError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		if body.Sites != nil {
+			sites := make([]struct {
+				Site jamf.BaseType `json:"site"`
+			}, 0, len(body.Sites.Sites))
+			for _, ref := range body.Sites.Sites {
+				site, found := s.siteLocked(ref.ID)
+				if !found || site.ID == jamf.NoSiteID {
+					s.mu.Unlock()
+					writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("site %d not found", ref.ID))
+					return
+				}
+				sites = append(sites, struct {
+					Site jamf.BaseType `json:"site"`
+				}{Site: site})
+			}
+			u.Sites = sites
+		}
+		s.mu.Unlock()
+
+		// updateuserbyid, like createuserbyid, documents only the updated
+		// resource's ID — see https://developer.jamf.com/jamf-pro/reference/updateuserbyid.
+		writeJSON(w, http.StatusCreated, createResponse{ID: id})
+
 	case http.MethodDelete:
 		s.mu.Lock()
 		_, ok := s.users[id]
@@ -433,6 +900,32 @@ func (s *server) handleUserByName(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, jamf.UserResponse{User: *u})
 }
 
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findusersbyemail
+func (s *server) handleUsersByEmail(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+	email := pathTail(r.URL.Path, "/JSSResource/users/email/")
+
+	s.mu.Lock()
+	var users []jamf.User
+	for _, u := range s.userList {
+		if strings.EqualFold(u.Email, email) {
+			users = append(users, *u)
+		}
+	}
+	s.mu.Unlock()
+	if len(users) == 0 {
+		writeJSONError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, jamf.UsersByEmailResponse{Users: users})
+}
+
 // findUserByNameLocked assumes the caller already holds s.mu.
 func (s *server) findUserByNameLocked(name string) (*jamf.User, bool) {
 	for _, u := range s.userList {
@@ -470,12 +963,13 @@ func (s *server) handleListAccounts(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, jamf.AccountsResponse{Accounts: jamf.BaseAccount{Users: users, Groups: groups}})
 }
 
-// handleAccountByID dispatches GET / POST (create) / DELETE on
-// /JSSResource/accounts/userid/{id}.
+// handleAccountByID dispatches GET / POST (create) / PUT (update) / DELETE
+// on /JSSResource/accounts/userid/{id}.
 //
 // Doc URLs:
 //   - https://developer.jamf.com/jamf-pro/reference/findaccountsbyid
 //   - https://developer.jamf.com/jamf-pro/reference/createaccountbyid
+//   - https://developer.jamf.com/jamf-pro/reference/updateaccountbyid
 //   - https://developer.jamf.com/jamf-pro/reference/deleteaccountbyid
 func (s *server) handleAccountByID(w http.ResponseWriter, r *http.Request) {
 	if !s.requireBearer(w, r) {
@@ -494,6 +988,7 @@ func (s *server) handleAccountByID(w http.ResponseWriter, r *http.Request) {
 		var cp jamf.UserAccount
 		if ok {
 			cp = *a
+			cp.Groups = s.accountGroupsLocked(id)
 		}
 		s.mu.Unlock()
 		if !ok {
@@ -546,12 +1041,14 @@ func (s *server) handleAccountByID(w http.ResponseWriter, r *http.Request) {
 			Enabled:      body.Enabled,
 			AccessLevel:  body.AccessLevel,
 			PrivilegeSet: body.PrivilegeSet,
+			LDAPServer:   noLDAPServer,
 		}
 		if body.Privileges != nil {
 			a.Privileges = *body.Privileges
 		}
 		s.accounts[a.ID] = a
 		s.accountList = append(s.accountList, a)
+		s.accountPasswords[a.ID] = body.Password
 		id := a.ID
 		s.mu.Unlock()
 
@@ -560,6 +1057,85 @@ func (s *server) handleAccountByID(w http.ResponseWriter, r *http.Request) {
 		// ID — see https://developer.jamf.com/jamf-pro/reference/createaccountbyid.
 		writeJSON(w, http.StatusCreated, createResponse{ID: id})
 
+	case http.MethodPut:
+		body, ok := decodeXMLBody[jamf.UserAccountUpdateBody](w, r)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		a, ok := s.accounts[id]
+		if !ok {
+			s.mu.Unlock()
+			writeJSONError(w, http.StatusNotFound, "account not found")
+			return
+		}
+		if body.PrivilegeSet != "" && !slices.Contains(validPrivilegeSets, body.PrivilegeSet) {
+			s.mu.Unlock()
+			writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid privilege_set %q", body.PrivilegeSet))
+			return
+		}
+		if body.Enabled != "" && !slices.Contains(validEnabledValues, body.Enabled) {
+			s.mu.Unlock()
+			writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid enabled %q", body.Enabled))
+			return
+		}
+		// NOTE: a directory account's password lives in its LDAP server, so
+		// setting one is rejected here on the assumption Jamf does the same;
+		// unverified against a live tenant.
+		if body.Password != "" && a.DirectoryUser {
+			s.mu.Unlock()
+			writeJSONError(w, http.StatusBadRequest, "a directory account has no Jamf password")
+			return
+		}
+		var site jamf.BaseType
+		if body.Site != nil {
+			var found bool
+			if site, found = s.siteLocked(body.Site.ID); !found {
+				s.mu.Unlock()
+				writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("site %d not found", body.Site.ID))
+				return
+			}
+			// NOTE: a Site Access account without a site is rejected here on
+			// the assumption Jamf does the same; unverified against a live
+			// tenant.
+			if site.ID == jamf.NoSiteID && a.AccessLevel == accessLevelSiteAccess {
+				s.mu.Unlock()
+				writeJSONError(w, http.StatusBadRequest, "a Site Access account must belong to a site")
+				return
+			}
+		}
+		if body.Groups != nil {
+			for _, ref := range body.Groups.Groups {
+				if _, exists := s.groups[ref.ID]; !exists {
+					s.mu.Unlock()
+					writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("group %d not found", ref.ID))
+					return
+				}
+			}
+			s.setAccountGroupsLocked(a, body.Groups.Groups)
+		}
+		if body.PrivilegeSet != "" {
+			a.PrivilegeSet = body.PrivilegeSet
+		}
+		if body.Privileges != nil {
+			a.Privileges = jamf.Privileges(*body.Privileges)
+		}
+		if body.Site != nil {
+			a.Site = site
+		}
+		if body.Enabled != "" {
+			a.Enabled = body.Enabled
+		}
+		if body.Password != "" {
+			s.accountPasswords[id] = body.Password
+		}
+		s.mu.Unlock()
+
+		// updateaccountbyid declares 201 and, like create, documents only the
+		// updated resource's ID — see https://developer.jamf.com/jamf-pro/reference/updateaccountbyid.
+		writeJSON(w, http.StatusCreated, createResponse{ID: id})
+
 	case http.MethodDelete:
 		s.mu.Lock()
 		_, ok := s.accounts[id]
@@ -592,12 +1168,17 @@ func (s *server) handleAccountByName(w http.ResponseWriter, r *http.Request) {
 
 	s.mu.Lock()
 	a, ok := s.findAccountByNameLocked(name)
+	var cp jamf.UserAccount
+	if ok {
+		cp = *a
+		cp.Groups = s.accountGroupsLocked(a.ID)
+	}
 	s.mu.Unlock()
 	if !ok {
 		writeJSONError(w, http.StatusNotFound, "account not found")
 		return
 	}
-	writeJSON(w, http.StatusOK, jamf.UserAccountResponse{UserAccount: *a})
+	writeJSON(w, http.StatusOK, jamf.UserAccountResponse{UserAccount: cp})
 }
 
 // findAccountByNameLocked assumes the caller already holds s.mu.
@@ -611,33 +1192,114 @@ func (s *server) findAccountByNameLocked(name string) (*jamf.UserAccount, bool)
 	return nil, false
 }
 
-// Doc URL: https://developer.jamf.com/jamf-pro/reference/findgroupsbyid
+// accountGroupsLocked derives an account's group list from the seeded group
+// memberships, so the account and group views never disagree. Assumes the
+// caller already holds s.mu.
+func (s *server) accountGroupsLocked(accountID int) []jamf.BaseType {
+	var groups []jamf.BaseType
+	for _, g := range s.groupList {
+		if slices.ContainsFunc(g.Members, func(m jamf.BaseType) bool { return m.ID == accountID }) {
+			groups = append(groups, jamf.BaseType{ID: g.ID, Name: g.Name})
+		}
+	}
+	return groups
+}
+
+// setAccountGroupsLocked replaces account's group memberships with refs,
+// mirroring the Classic API's replace semantics for an account's <groups>.
+// Member slices are rebuilt rather than edited in place because GET handlers
+// hand out shallow copies. Assumes the caller already holds s.mu.
+func (s *server) setAccountGroupsLocked(account *jamf.UserAccount, refs []jamf.IDRef) {
+	for _, g := range s.groupList {
+		members := make([]jamf.BaseType, 0, len(g.Members)+1)
+		for _, m := range g.Members {
+			if m.ID != account.ID {
+				members = append(members, m)
+			}
+		}
+		if slices.ContainsFunc(refs, func(ref jamf.IDRef) bool { return ref.ID == g.ID }) {
+			members = append(members, jamf.BaseType{ID: account.ID, Name: account.Name})
+		}
+		g.Members = members
+	}
+}
+
+// handleGroupByID dispatches GET / PUT (update) on
+// /JSSResource/accounts/groupid/{id}.
+//
+// Doc URLs:
+//   - https://developer.jamf.com/jamf-pro/reference/findgroupsbyid
+//   - https://developer.jamf.com/jamf-pro/reference/updategroupbyid
 func (s *server) handleGroupByID(w http.ResponseWriter, r *http.Request) {
 	if !s.requireBearer(w, r) {
 		return
 	}
-	if r.Method != http.MethodGet {
-		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
-		return
-	}
 	id, err := pathID(r.URL.Path, "/JSSResource/accounts/groupid/")
 	if err != nil {
 		writeJSONError(w, http.StatusBadRequest, "invalid id")
 		return
 	}
 
-	s.mu.Lock()
-	g, ok := s.groups[id]
-	var cp jamf.Group
-	if ok {
-		cp = *g
-	}
-	s.mu.Unlock()
-	if !ok {
-		writeJSONError(w, http.StatusNotFound, "group not found")
-		return
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		g, ok := s.groups[id]
+		var cp jamf.Group
+		if ok {
+			cp = *g
+		}
+		s.mu.Unlock()
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "group not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, jamf.GroupResponse{Group: cp})
+
+	case http.MethodPut:
+		body, ok := decodeXMLBody[jamf.GroupUpdateBody](w, r)
+		if !ok {
+			return
+		}
+		if body.PrivilegeSet != "" && !slices.Contains(validPrivilegeSets, body.PrivilegeSet) {
+			writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid privilege_set %q", body.PrivilegeSet))
+			return
+		}
+
+		s.mu.Lock()
+		g, ok := s.groups[id]
+		if !ok {
+			s.mu.Unlock()
+			writeJSONError(w, http.StatusNotFound, "group not found")
+			return
+		}
+		var site jamf.BaseType
+		if body.Site != nil {
+			var found bool
+			if site, found = s.siteLocked(body.Site.ID); !found {
+				s.mu.Unlock()
+				writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("site %d not found", body.Site.ID))
+				return
+			}
+			if site.ID == jamf.NoSiteID && g.AccessLevel == accessLevelSiteAccess {
+				s.mu.Unlock()
+				writeJSONError(w, http.StatusBadRequest, "a Site Access group must belong to a site")
+				return
+			}
+			g.Site = site
+		}
+		if body.PrivilegeSet != "" {
+			g.PrivilegeSet = body.PrivilegeSet
+		}
+		if body.Privileges != nil {
+			g.Privileges = jamf.Privileges(*body.Privileges)
+		}
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusCreated, createResponse{ID: id})
+
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
 	}
-	writeJSON(w, http.StatusOK, jamf.GroupResponse{Group: cp})
 }
 
 // ── User groups (/JSSResource/usergroups) ───────────────────────────────────
@@ -661,37 +1323,1062 @@ func (s *server) handleListUserGroups(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, jamf.UserGroupsResponse{UserGroups: minimal})
 }
 
-// Doc URL: https://developer.jamf.com/jamf-pro/reference/findusergroupsbyid
+// handleUserGroupByID dispatches GET / PUT (static membership changes) on
+// /JSSResource/usergroups/id/{id}.
+//
+// Doc URLs:
+//   - https://developer.jamf.com/jamf-pro/reference/findusergroupsbyid
+//   - https://developer.jamf.com/jamf-pro/reference/updateusergroupbyid
 func (s *server) handleUserGroupByID(w http.ResponseWriter, r *http.Request) {
 	if !s.requireBearer(w, r) {
 		return
 	}
-	if r.Method != http.MethodGet {
-		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
-		return
-	}
 	id, err := pathID(r.URL.Path, "/JSSResource/usergroups/id/")
 	if err != nil {
 		writeJSONError(w, http.StatusBadRequest, "invalid id")
 		return
 	}
 
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		g, ok := s.userGroups[id]
+		var cp jamf.UserGroup
+		if ok {
+			cp = *g
+		}
+		s.mu.Unlock()
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "user group not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, jamf.UserGroupResponse{UserGroup: cp})
+
+	case http.MethodPut:
+		body, ok := decodeXMLBody[jamf.UserGroupUpdateBody](w, r)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		g, ok := s.userGroups[id]
+		if !ok {
+			s.mu.Unlock()
+			writeJSONError(w, http.StatusNotFound, "user group not found")
+			return
+		}
+		// NOTE: Jamf's docs don't say how a smart group answers a
+		// user_additions/user_deletions PUT; rejecting it mirrors the
+		// connector's own refusal. Unverified against a live tenant.
+		if g.IsSmart && (body.UserAdditions != nil || body.UserDeletions != nil) {
+			s.mu.Unlock()
+			writeJSONError(w, http.StatusBadRequest, "membership of a smart user group can't be changed directly")
+			return
+		}
+		var site jamf.BaseType
+		if body.Site != nil {
+			var found bool
+			if site, found = s.siteLocked(body.Site.ID); !found {
+				s.mu.Unlock()
+				writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("site %d not found", body.Site.ID))
+				return
+			}
+		}
+		// Rebuilt rather than edited in place because GET hands out shallow copies.
+		users := slices.Clone(g.Users)
+		if body.UserDeletions != nil {
+			users = slices.DeleteFunc(users, func(u jamf.User) bool {
+				return slices.ContainsFunc(body.UserDeletions.Users, func(ref jamf.IDRef) bool { return ref.ID == u.ID })
+			})
+		}
+		if body.UserAdditions != nil {
+			for _, ref := range body.UserAdditions.Users {
+				u, exists := s.users[ref.ID]
+				if !exists {
+					s.mu.Unlock()
+					writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("user %d not found", ref.ID))
+					return
+				}
+				if !slices.ContainsFunc(users, func(m jamf.User) bool { return m.ID == u.ID }) {
+					users = append(users, *u)
+				}
+			}
+		}
+		g.Users = users
+		if body.Site != nil {
+			g.Site = jamf.Site{BaseType: site}
+		}
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusCreated, createResponse{ID: id})
+
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// ── Computer groups (/JSSResource/computergroups) ───────────────────────────
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findcomputergroups
+func (s *server) handleListComputerGroups(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+
+	s.mu.Lock()
+	minimal := make([]jamf.ComputerGroup, 0, len(s.computerGroupList))
+	for _, g := range s.computerGroupList {
+		minimal = append(minimal, jamf.ComputerGroup{BaseType: jamf.BaseType{ID: g.ID, Name: g.Name}, IsSmart: g.IsSmart})
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, jamf.ComputerGroupsResponse{ComputerGroups: minimal})
+}
+
+// handleComputerGroupByID dispatches GET / PUT (static membership changes)
+// on /JSSResource/computergroups/id/{id}.
+//
+// Doc URLs:
+//   - https://developer.jamf.com/jamf-pro/reference/findcomputergroupsbyid
+//   - https://developer.jamf.com/jamf-pro/reference/updatecomputergroupbyid
+func (s *server) handleComputerGroupByID(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	id, err := pathID(r.URL.Path, "/JSSResource/computergroups/id/")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		g, ok := s.computerGroups[id]
+		var cp jamf.ComputerGroup
+		if ok {
+			cp = *g
+		}
+		s.mu.Unlock()
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "computer group not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, jamf.ComputerGroupResponse{ComputerGroup: cp})
+
+	case http.MethodPut:
+		body, ok := decodeXMLBody[jamf.ComputerGroupUpdateBody](w, r)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		g, ok := s.computerGroups[id]
+		if !ok {
+			s.mu.Unlock()
+			writeJSONError(w, http.StatusNotFound, "computer group not found")
+			return
+		}
+		// NOTE: as with user groups, rejecting a membership change to a smart
+		// group mirrors the connector's own refusal. Unverified against a
+		// live tenant.
+		if g.IsSmart && (body.ComputerAdditions != nil || body.ComputerDeletions != nil) {
+			s.mu.Unlock()
+			writeJSONError(w, http.StatusBadRequest, "membership of a smart computer group can't be changed directly")
+			return
+		}
+		// Rebuilt rather than edited in place because GET hands out shallow copies.
+		computers := slices.Clone(g.Computers)
+		if body.ComputerDeletions != nil {
+			computers = slices.DeleteFunc(computers, func(c jamf.ComputerGroupMember) bool {
+				return slices.ContainsFunc(body.ComputerDeletions.Computers, func(ref jamf.IDRef) bool { return ref.ID == c.ID })
+			})
+		}
+		if body.ComputerAdditions != nil {
+			for _, ref := range body.ComputerAdditions.Computers {
+				c, exists := s.computers[ref.ID]
+				if !exists {
+					s.mu.Unlock()
+					writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("computer %d not found", ref.ID))
+					return
+				}
+				if !slices.ContainsFunc(computers, func(m jamf.ComputerGroupMember) bool { return m.ID == c.ID }) {
+					computers = append(computers, c)
+				}
+			}
+		}
+		g.Computers = computers
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusCreated, createResponse{ID: id})
+
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// ── Mobile device groups (/JSSResource/mobiledevicegroups) ──────────────────
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findmobiledevicegroups
+func (s *server) handleListMobileDeviceGroups(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+
+	s.mu.Lock()
+	minimal := make([]jamf.MobileDeviceGroup, 0, len(s.mobileDeviceGroupList))
+	for _, g := range s.mobileDeviceGroupList {
+		minimal = append(minimal, jamf.MobileDeviceGroup{BaseType: jamf.BaseType{ID: g.ID, Name: g.Name}, IsSmart: g.IsSmart})
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, jamf.MobileDeviceGroupsResponse{MobileDeviceGroups: minimal})
+}
+
+// handleMobileDeviceGroupByID serves GET on
+// /JSSResource/mobiledevicegroups/id/{id}.
+//
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findmobiledevicegroupsbyid
+func (s *server) handleMobileDeviceGroupByID(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	id, err := pathID(r.URL.Path, "/JSSResource/mobiledevicegroups/id/")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		g, ok := s.mobileDeviceGroups[id]
+		var cp jamf.MobileDeviceGroup
+		if ok {
+			cp = *g
+		}
+		s.mu.Unlock()
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "mobile device group not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, jamf.MobileDeviceGroupResponse{MobileDeviceGroup: cp})
+
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// ── LDAP servers (/JSSResource/ldapservers) ─────────────────────────────────
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findldapservers
+func (s *server) handleListLDAPServers(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+
+	s.mu.Lock()
+	minimal := make([]jamf.BaseType, 0, len(s.ldapServers))
+	for _, l := range s.ldapServers {
+		minimal = append(minimal, l.Connection.BaseType)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, jamf.LDAPServersResponse{LDAPServers: minimal})
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findldapserversbyid
+func (s *server) handleLDAPServerByID(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+	id, err := pathID(r.URL.Path, "/JSSResource/ldapservers/id/")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	s.mu.Lock()
+	idx := slices.IndexFunc(s.ldapServers, func(l jamf.LDAPServer) bool { return l.Connection.ID == id })
+	var cp jamf.LDAPServer
+	if idx >= 0 {
+		cp = s.ldapServers[idx]
+	}
+	s.mu.Unlock()
+	if idx < 0 {
+		writeJSONError(w, http.StatusNotFound, "LDAP server not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, jamf.LDAPServerResponse{LDAPServer: cp})
+}
+
+// ── Devices (/api/v1/computers-inventory, /api/v2/mobile-devices) ───────────
+
+// mockComputer builds a managed computer's inventory record with every
+// section the connector reads.
+func mockComputer(
+	computer jamf.ComputerGroupMember,
+	model, modelIdentifier string,
+	location *jamf.ComputerUserAndLocation,
+	localAccounts ...jamf.ComputerLocalUserAccount,
+) jamf.ComputerInventory {
+	return jamf.ComputerInventory{
+		ID:   strconv.Itoa(computer.ID),
+		UDID: fmt.Sprintf("5A1B2C3D-0000-4000-8000-%012d", computer.ID),
+		General: &jamf.ComputerGeneral{
+			Name:             computer.Name,
+			ManagementID:     fmt.Sprintf("7C1D0F2E-0000-4000-8000-%012d", computer.ID),
+			LastEnrolledDate: "2024-03-01T12:00:00.000Z",
+			MDMCapable:       &jamf.ComputerMDMCapable{Capable: true},
+			RemoteManagement: &jamf.ComputerRemoteManagement{Managed: true},
+		},
+		Hardware:        &jamf.ComputerHardware{Make: "Apple", Model: model, ModelIdentifier: modelIdentifier, SerialNumber: computer.SerialNumber},
+		OperatingSystem: &jamf.ComputerOperatingSystem{Name: "macOS", Version: "14.4", Build: "23E214"},
+		UserAndLocation: location,
+
+		LocalUserAccounts: localAccounts,
+	}
+}
+
+// mockLocalAccount builds a local macOS account for a computer's
+// LOCAL_USER_ACCOUNTS section.
+func mockLocalAccount(uid int, username, fullName string, admin bool) jamf.ComputerLocalUserAccount {
+	return jamf.ComputerLocalUserAccount{
+		UID:           strconv.Itoa(uid),
+		UserGUID:      fmt.Sprintf("A1B2C3D4-0000-4000-8000-%012d", uid),
+		Username:      username,
+		FullName:      fullName,
+		Admin:         admin,
+		HomeDirectory: "/Users/" + username,
+	}
+}
+
+// mockMobileDevice builds a managed, supervised mobile device's list record.
+func mockMobileDevice(device jamf.MobileDeviceGroupMember, model, modelIdentifier, username string) jamf.MobileDevice {
+	return jamf.MobileDevice{
+		ID:              strconv.Itoa(device.ID),
+		Name:            device.Name,
+		SerialNumber:    device.SerialNumber,
+		UDID:            device.UDID,
+		ManagementID:    fmt.Sprintf("9E3A5B7C-0000-4000-8000-%012d", device.ID),
+		Model:           model,
+		ModelIdentifier: modelIdentifier,
+		Username:        username,
+		Type:            "ios",
+		Managed:         true,
+		Supervised:      true,
+		OSVersion:       "17.4",
+		OSBuild:         "21E219",
+	}
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/get_v1-computers-inventory
+func (s *server) handleComputersInventory(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+
+	// Like Jamf, only the requested sections are populated; GENERAL is the
+	// default.
+	sections := r.URL.Query()["section"]
+	if len(sections) == 0 {
+		sections = []string{"GENERAL"}
+	}
+	s.mu.Lock()
+	computers := make([]jamf.ComputerInventory, 0, len(s.computerInventory))
+	for _, c := range s.computerInventory {
+		cp := jamf.ComputerInventory{ID: c.ID, UDID: c.UDID}
+		if slices.Contains(sections, "GENERAL") {
+			cp.General = c.General
+		}
+		if slices.Contains(sections, "HARDWARE") {
+			cp.Hardware = c.Hardware
+		}
+		if slices.Contains(sections, "OPERATING_SYSTEM") {
+			cp.OperatingSystem = c.OperatingSystem
+		}
+		if slices.Contains(sections, "USER_AND_LOCATION") {
+			cp.UserAndLocation = c.UserAndLocation
+		}
+		if slices.Contains(sections, "LOCAL_USER_ACCOUNTS") {
+			cp.LocalUserAccounts = c.LocalUserAccounts
+		}
+		computers = append(computers, cp)
+	}
+	s.mu.Unlock()
+	writePage(w, r, computers)
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/get_v2-mobile-devices
+func (s *server) handleMobileDevices(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+	s.mu.Lock()
+	devices := slices.Clone(s.mobileDevices)
+	s.mu.Unlock()
+	writePage(w, r, devices)
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/get_v2-mobile-devices-detail
+func (s *server) handleMobileDevicesDetail(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+
+	// Only USER_AND_LOCATION is mocked, so any other section request gets
+	// bare records.
+	withLocation := slices.Contains(r.URL.Query()["section"], "USER_AND_LOCATION")
+	s.mu.Lock()
+	devices := make([]jamf.MobileDeviceDetail, 0, len(s.mobileDeviceDetails))
+	for _, d := range s.mobileDeviceDetails {
+		cp := jamf.MobileDeviceDetail{MobileDeviceID: d.MobileDeviceID}
+		if withLocation {
+			cp.UserAndLocation = d.UserAndLocation
+		}
+		devices = append(devices, cp)
+	}
+	s.mu.Unlock()
+	writePage(w, r, devices)
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/get_v1-computers-inventory-detail-id
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/patch_v1-computers-inventory-detail-id
+func (s *server) handleComputerInventoryDetail(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	id, err := pathID(r.URL.Path, "/api/v1/computers-inventory-detail/")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		var (
+			cp jamf.ComputerInventory
+			ok bool
+		)
+		if i := slices.IndexFunc(s.computerInventory, func(c jamf.ComputerInventory) bool { return c.ID == strconv.Itoa(id) }); i >= 0 {
+			cp, ok = s.computerInventory[i], true
+		}
+		s.mu.Unlock()
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "computer not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, cp)
+
+	case http.MethodPatch:
+		body, ok := decodeJSONBody[jamf.ComputerInventoryUpdateBody](w, r)
+		if !ok {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		i := slices.IndexFunc(s.computerInventory, func(c jamf.ComputerInventory) bool { return c.ID == strconv.Itoa(id) })
+		if i < 0 {
+			writeJSONError(w, http.StatusNotFound, "computer not found")
+			return
+		}
+		if update := body.UserAndLocation; update != nil {
+			// Replace rather than modify the section, which earlier
+			// responses may still be encoding.
+			location := jamf.ComputerUserAndLocation{}
+			if current := s.computerInventory[i].UserAndLocation; current != nil {
+				location = *current
+			}
+			setIfPresent(&location.Username, update.Username)
+			setIfPresent(&location.Realname, update.Realname)
+			setIfPresent(&location.Email, update.Email)
+			s.computerInventory[i].UserAndLocation = &location
+		}
+		writeJSON(w, http.StatusOK, s.computerInventory[i])
+
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET or PATCH")
+	}
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/get_v2-mobile-devices-id
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/patch_v2-mobile-devices-id
+func (s *server) handleMobileDeviceByID(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	id, err := pathID(r.URL.Path, "/api/v2/mobile-devices/")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		var (
+			cp jamf.MobileDevice
+			ok bool
+		)
+		if i := slices.IndexFunc(s.mobileDevices, func(d jamf.MobileDevice) bool { return d.ID == strconv.Itoa(id) }); i >= 0 {
+			cp, ok = s.mobileDevices[i], true
+		}
+		s.mu.Unlock()
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "mobile device not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, cp)
+
+	case http.MethodPatch:
+		body, ok := decodeJSONBody[jamf.MobileDeviceUpdateBody](w, r)
+		if !ok {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		i := slices.IndexFunc(s.mobileDevices, func(d jamf.MobileDevice) bool { return d.ID == strconv.Itoa(id) })
+		if i < 0 {
+			writeJSONError(w, http.StatusNotFound, "mobile device not found")
+			return
+		}
+		if update := body.Location; update != nil {
+			setIfPresent(&s.mobileDevices[i].Username, update.Username)
+			// The detail records carry the rest of the location.
+			if j := slices.IndexFunc(s.mobileDeviceDetails, func(d jamf.MobileDeviceDetail) bool { return d.MobileDeviceID == strconv.Itoa(id) }); j >= 0 {
+				location := jamf.MobileDeviceUserAndLocation{}
+				if current := s.mobileDeviceDetails[j].UserAndLocation; current != nil {
+					location = *current
+				}
+				setIfPresent(&location.Username, update.Username)
+				setIfPresent(&location.EmailAddress, update.EmailAddress)
+				s.mobileDeviceDetails[j].UserAndLocation = &location
+			}
+		}
+		writeJSON(w, http.StatusOK, s.mobileDevices[i])
+
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET or PATCH")
+	}
+}
+
+// setIfPresent applies one field of a partial update.
+func setIfPresent(field *string, value *string) {
+	if value != nil {
+		*field = *value
+	}
+}
+
+// ── MDM commands (/api/v2/mdm/commands) ────────────────────────────────────
+
+// mdmCommand is an MDM command the mock queued.
+type mdmCommand struct {
+	uuid         string
+	commandType  string
+	managementID string
+	pin          string
+}
+
+// queueMDMCommand records a command and returns its UUID. Callers hold s.mu.
+func (s *server) queueMDMCommand(commandType, managementID, pin string) string {
+	uuid := fmt.Sprintf("C0FFEE00-0000-4000-8000-%012d", len(s.mdmCommands)+1)
+	s.mdmCommands = append(s.mdmCommands, mdmCommand{uuid: uuid, commandType: commandType, managementID: managementID, pin: pin})
+	return uuid
+}
+
+// isManagementID reports whether a computer or mobile device has the given
+// management ID. Callers hold s.mu.
+func (s *server) isManagementID(managementID string) bool {
+	return slices.ContainsFunc(s.computerInventory, func(c jamf.ComputerInventory) bool {
+		return c.General != nil && c.General.ManagementID == managementID
+	}) || slices.ContainsFunc(s.mobileDevices, func(d jamf.MobileDevice) bool { return d.ManagementID == managementID })
+}
+
+// managementIDOf returns the management ID of the computer or mobile device
+// with the given UDID. Callers hold s.mu.
+func (s *server) managementIDOf(udid string) (string, bool) {
+	for _, c := range s.computerInventory {
+		if c.UDID == udid && c.General != nil {
+			return c.General.ManagementID, true
+		}
+	}
+	for _, d := range s.mobileDevices {
+		if d.UDID == udid {
+			return d.ManagementID, true
+		}
+	}
+	return "", false
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/post_v2-mdm-commands
+func (s *server) handleMDMCommands(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be POST")
+		return
+	}
+	body, ok := decodeJSONBody[jamf.MDMCommandBody](w, r)
+	if !ok {
+		return
+	}
+	switch body.CommandData.CommandType {
+	case jamf.MDMCommandDeviceLock, jamf.MDMCommandEraseDevice, jamf.MDMCommandRestartDevice:
+	default:
+		writeJSONError(w, http.StatusBadRequest, "unsupported command type "+body.CommandData.CommandType)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := make([]jamf.MDMCommandResult, 0, len(body.ClientData))
+	for _, client := range body.ClientData {
+		if !s.isManagementID(client.ManagementID) {
+			writeJSONError(w, http.StatusBadRequest, "unknown management id "+client.ManagementID)
+			return
+		}
+	}
+	for _, client := range body.ClientData {
+		uuid := s.queueMDMCommand(body.CommandData.CommandType, client.ManagementID, body.CommandData.Pin)
+		results = append(results, jamf.MDMCommandResult{ID: uuid, Href: "/api/v2/mdm/commands?filter=uuid==" + uuid})
+	}
+	writeJSON(w, http.StatusCreated, results)
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/post_v1-computer-inventory-id-remove-mdm-profile
+func (s *server) handleRemoveMDMProfile(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be POST")
+		return
+	}
+	id, err := pathID(strings.TrimSuffix(r.URL.Path, "/remove-mdm-profile"), "/api/v1/computer-inventory/")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.computerInventory, func(c jamf.ComputerInventory) bool { return c.ID == strconv.Itoa(id) })
+	if i < 0 {
+		writeJSONError(w, http.StatusNotFound, "computer not found")
+		return
+	}
+	uuid := s.queueMDMCommand("REMOVE_MDM_PROFILE", s.computerInventory[i].General.ManagementID, "")
+	writeJSON(w, http.StatusOK, jamf.RemoveMDMProfileResponse{DeviceID: strconv.Itoa(id), CommandUUID: uuid})
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/post_v1-mdm-renew-profile
+func (s *server) handleRenewMDMProfile(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be POST")
+		return
+	}
+	body, ok := decodeJSONBody[jamf.RenewMDMProfileBody](w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var resp jamf.RenewMDMProfileResponse
+	resp.UDIDsNotProcessed.UDIDs = []string{}
+	for _, udid := range body.UDIDs {
+		managementID, ok := s.managementIDOf(udid)
+		if !ok {
+			resp.UDIDsNotProcessed.UDIDs = append(resp.UDIDsNotProcessed.UDIDs, udid)
+			continue
+		}
+		s.queueMDMCommand("RENEW_MDM_PROFILE", managementID, "")
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// ── Policies (/JSSResource/policies) ────────────────────────────────────────
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findpolicies
+func (s *server) handleListPolicies(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+
+	s.mu.Lock()
+	minimal := make([]jamf.BaseType, 0, len(s.policyList))
+	for _, p := range s.policyList {
+		minimal = append(minimal, p.General.BaseType)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, jamf.PoliciesResponse{Policies: minimal})
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findpoliciesbyid
+func (s *server) handlePolicyByID(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+	id, err := pathID(r.URL.Path, "/JSSResource/policies/id/")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	s.mu.Lock()
+	p, ok := s.policies[id]
+	var cp jamf.Policy
+	if ok {
+		cp = *p
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "policy not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, jamf.PolicyResponse{Policy: cp})
+}
+
+// ── Configuration profiles ──────────────────────────────────────────────────
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findosxconfigurationprofiles
+func (s *server) handleListMacOSConfigurationProfiles(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+
+	s.mu.Lock()
+	minimal := make([]jamf.BaseType, 0, len(s.macOSConfigurationProfiles))
+	for _, p := range s.macOSConfigurationProfiles {
+		minimal = append(minimal, p.General.BaseType)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, jamf.MacOSConfigurationProfilesResponse{ConfigurationProfiles: minimal})
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findosxconfigurationprofilesbyid
+func (s *server) handleMacOSConfigurationProfileByID(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+	id, err := pathID(r.URL.Path, "/JSSResource/osxconfigurationprofiles/id/")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
 	s.mu.Lock()
-	g, ok := s.userGroups[id]
-	var cp jamf.UserGroup
-	if ok {
-		cp = *g
+	var (
+		cp jamf.MacOSConfigurationProfile
+		ok bool
+	)
+	if i := slices.IndexFunc(s.macOSConfigurationProfiles, func(p *jamf.MacOSConfigurationProfile) bool { return p.General.ID == id }); i >= 0 {
+		cp, ok = *s.macOSConfigurationProfiles[i], true
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "configuration profile not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, jamf.MacOSConfigurationProfileResponse{ConfigurationProfile: cp})
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findmobiledeviceconfigurationprofiles
+func (s *server) handleListMobileDeviceConfigurationProfiles(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+
+	s.mu.Lock()
+	minimal := make([]jamf.BaseType, 0, len(s.mobileDeviceConfigurationProfiles))
+	for _, p := range s.mobileDeviceConfigurationProfiles {
+		minimal = append(minimal, p.General.BaseType)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, jamf.MobileDeviceConfigurationProfilesResponse{ConfigurationProfiles: minimal})
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findmobiledeviceconfigurationprofilesbyid
+func (s *server) handleMobileDeviceConfigurationProfileByID(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+	id, err := pathID(r.URL.Path, "/JSSResource/mobiledeviceconfigurationprofiles/id/")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	s.mu.Lock()
+	var (
+		cp jamf.MobileDeviceConfigurationProfile
+		ok bool
+	)
+	if i := slices.IndexFunc(s.mobileDeviceConfigurationProfiles, func(p *jamf.MobileDeviceConfigurationProfile) bool { return p.General.ID == id }); i >= 0 {
+		cp, ok = *s.mobileDeviceConfigurationProfiles[i], true
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "configuration profile not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, jamf.MobileDeviceConfigurationProfileResponse{ConfigurationProfile: cp})
+}
+
+// ── Apps (/JSSResource/macapplications, /JSSResource/mobiledeviceapplications) ─
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findmacapps
+func (s *server) handleListMacApplications(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+
+	s.mu.Lock()
+	minimal := make([]jamf.BaseType, 0, len(s.macApplications))
+	for _, a := range s.macApplications {
+		minimal = append(minimal, a.General.BaseType)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, jamf.MacApplicationsResponse{MacApplications: minimal})
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findmacappsbyid
+func (s *server) handleMacApplicationByID(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+	id, err := pathID(r.URL.Path, "/JSSResource/macapplications/id/")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	s.mu.Lock()
+	var (
+		cp jamf.MacApplication
+		ok bool
+	)
+	if i := slices.IndexFunc(s.macApplications, func(a *jamf.MacApplication) bool { return a.General.ID == id }); i >= 0 {
+		cp, ok = *s.macApplications[i], true
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "mac application not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, jamf.MacApplicationResponse{MacApplication: cp})
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findmobiledeviceapplications
+func (s *server) handleListMobileDeviceApplications(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+
+	s.mu.Lock()
+	minimal := make([]jamf.BaseType, 0, len(s.mobileDeviceApplications))
+	for _, a := range s.mobileDeviceApplications {
+		minimal = append(minimal, a.General.BaseType)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, jamf.MobileDeviceApplicationsResponse{MobileDeviceApplications: minimal})
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findmobiledeviceapplicationsbyid
+func (s *server) handleMobileDeviceApplicationByID(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+	id, err := pathID(r.URL.Path, "/JSSResource/mobiledeviceapplications/id/")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	s.mu.Lock()
+	var (
+		cp jamf.MobileDeviceApplication
+		ok bool
+	)
+	if i := slices.IndexFunc(s.mobileDeviceApplications, func(a *jamf.MobileDeviceApplication) bool { return a.General.ID == id }); i >= 0 {
+		cp, ok = *s.mobileDeviceApplications[i], true
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "mobile device application not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, jamf.MobileDeviceApplicationResponse{MobileDeviceApplication: cp})
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findvppadminassignment
+func (s *server) handleListVPPAssignments(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+
+	s.mu.Lock()
+	minimal := make([]jamf.BaseType, 0, len(s.vppAssignments))
+	for _, a := range s.vppAssignments {
+		minimal = append(minimal, a.General.BaseType)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, jamf.VPPAssignmentsResponse{VPPAssignments: minimal})
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/findvppassignmentbyid
+func (s *server) handleVPPAssignmentByID(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+	id, err := pathID(r.URL.Path, "/JSSResource/vppassignments/id/")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	s.mu.Lock()
+	var (
+		cp jamf.VPPAssignment
+		ok bool
+	)
+	if i := slices.IndexFunc(s.vppAssignments, func(a *jamf.VPPAssignment) bool { return a.General.ID == id }); i >= 0 {
+		cp, ok = *s.vppAssignments[i], true
 	}
 	s.mu.Unlock()
 	if !ok {
-		writeJSONError(w, http.StatusNotFound, "user group not found")
+		writeJSONError(w, http.StatusNotFound, "vpp assignment not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, jamf.VPPAssignmentResponse{VPPAssignment: cp})
+}
+
+// ── Buildings & departments (/api/v1/buildings, /api/v1/departments) ────────
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/get_v1-buildings
+func (s *server) handleBuildings(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+	s.mu.Lock()
+	buildings := slices.Clone(s.buildings)
+	s.mu.Unlock()
+	writePage(w, r, buildings)
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/get_v1-departments
+func (s *server) handleDepartments(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
 		return
 	}
-	writeJSON(w, http.StatusOK, jamf.UserGroupResponse{UserGroup: cp})
+	s.mu.Lock()
+	departments := slices.Clone(s.departments)
+	s.mu.Unlock()
+	writePage(w, r, departments)
 }
 
 // ── Sites & privileges ───────────────────────────────────────────────────────
 
+// siteLocked resolves a site ID from a request body; jamf.NoSiteID resolves
+// to Jamf's "None" site. Caller must hold s.mu.
+func (s *server) siteLocked(id int) (jamf.BaseType, bool) {
+	if id == jamf.NoSiteID {
+		return jamf.BaseType{ID: jamf.NoSiteID, Name: "None"}, true
+	}
+	for _, site := range s.sites {
+		if site.ID == id {
+			return site.BaseType, true
+		}
+	}
+	return jamf.BaseType{}, false
+}
+
 // Doc URL: https://developer.jamf.com/jamf-pro/reference/findsites
 func (s *server) handleListSites(w http.ResponseWriter, r *http.Request) {
 	if !s.requireBearer(w, r) {
@@ -722,8 +2409,72 @@ func (s *server) handleListPrivileges(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, jamf.PrivilegesResponse{Privileges: privileges})
 }
 
+// ── API roles & integrations (/api/v1/api-roles, /api/v1/api-integrations) ──
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/get_v1-api-roles
+func (s *server) handleListAPIRoles(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+	s.mu.Lock()
+	roles := append([]jamf.APIRole{}, s.apiRoles...)
+	s.mu.Unlock()
+	writePage(w, r, roles)
+}
+
+// Doc URL: https://developer.jamf.com/jamf-pro/reference/get_v1-api-integrations
+func (s *server) handleListAPIIntegrations(w http.ResponseWriter, r *http.Request) {
+	if !s.requireBearer(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method must be GET")
+		return
+	}
+	s.mu.Lock()
+	integrations := append([]jamf.APIIntegration{}, s.apiIntegrations...)
+	s.mu.Unlock()
+	writePage(w, r, integrations)
+}
+
 // ── Helpers ──────────────────────────────────────────────────────────────────
 
+// writePage writes the page of items selected by the Jamf Pro API's
+// zero-indexed page / page-size query parameters, in the API's
+// {totalCount, results} envelope.
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	page, pageSize := 0, 100
+	if v := r.URL.Query().Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeJSONError(w, http.StatusBadRequest, "page must be a non-negative integer")
+			return
+		}
+		page = n
+	}
+	if v := r.URL.Query().Get("page-size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeJSONError(w, http.StatusBadRequest, "page-size must be a positive integer")
+			return
+		}
+		pageSize = n
+	}
+
+	results := []T{}
+	if start := page * pageSize; start < len(items) {
+		results = items[start:min(start+pageSize, len(items))]
+	}
+	writeJSON(w, http.StatusOK, struct {
+		TotalCount int `json:"totalCount"`
+		Results    []T `json:"results"`
+	}{TotalCount: len(items), Results: results})
+}
+
 // decodeXMLBody enforces the Classic API's documented POST/PUT content-type
 // contract (XML only — see https://developer.jamf.com/jamf-pro/docs/getting-started-2)
 // and decodes the body into T. On any failure it writes the response itself
@@ -745,6 +2496,24 @@ func decodeXMLBody[T any](w http.ResponseWriter, r *http.Request) (T, bool) {
 	return body, true
 }
 
+// decodeJSONBody is decodeXMLBody for the Jamf Pro API, which takes JSON.
+func decodeJSONBody[T any](w http.ResponseWriter, r *http.Request) (T, bool) {
+	var zero T
+	contentType := r.Header.Get("Content-Type")
+	if !strings.Contains(strings.ToLower(contentType), "json") {
+		writeJSONError(w, http.StatusUnsupportedMediaType,
+			fmt.Sprintf("Jamf Pro API PATCH requires a JSON body; got Content-Type %q", contentType))
+		return zero, false
+	}
+
+	var body T
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "malformed JSON body: "+err.Error())
+		return zero, false
+	}
+	return body, true
+}
+
 // pathID extracts and parses the trailing numeric segment after prefix.
 func pathID(path, prefix string) (int, error) {
 	return strconv.Atoi(pathTail(path, prefix))
@@ -820,6 +2589,44 @@ func run() error {
 	if token == "" {
 		token = defaultToken
 	}
+	clientID := os.Getenv("JAMF_CLIENT_ID")
+	if clientID == "" {
+		clientID = defaultClientID
+	}
+	clientSecret := os.Getenv("JAMF_CLIENT_SECRET")
+	if clientSecret == "" {
+		clientSecret = defaultClientSecret
+	}
+	tokenTTL := defaultTokenTTL
+	if v := os.Getenv("JAMF_TOKEN_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid JAMF_TOKEN_TTL %q", v)
+		}
+		tokenTTL = d
+	}
+	faultEvery := 0
+	if v := os.Getenv("JAMF_FAULT_EVERY"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid JAMF_FAULT_EVERY %q", v)
+		}
+		faultEvery = n
+	}
+	faultStatus := http.StatusTooManyRequests
+	if v := os.Getenv("JAMF_FAULT_STATUS"); v != "" {
+		code, err := strconv.Atoi(v)
+		if err != nil || (code != http.StatusTooManyRequests && code != http.StatusServiceUnavailable) {
+			return fmt.Errorf("invalid JAMF_FAULT_STATUS %q: must be 429 or 503", v)
+		}
+		faultStatus = code
+	}
+	faultRetryAfter := os.Getenv("JAMF_FAULT_RETRY_AFTER")
+	if faultRetryAfter != "" {
+		if n, err := strconv.Atoi(faultRetryAfter); err != nil || n < 0 {
+			return fmt.Errorf("invalid JAMF_FAULT_RETRY_AFTER %q", faultRetryAfter)
+		}
+	}
 
 	ln, err := (&net.ListenConfig{}).Listen(context.Background(), "tcp", ":"+port)
 	if err != nil {
@@ -828,17 +2635,42 @@ func run() error {
 	port = strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
 	baseURL := "http://localhost:" + port
 
-	s := newServer(username, password, token)
+	s := newServer(username, password, clientID, clientSecret, token)
+	s.tokenTTL = tokenTTL
+	s.faultEvery = faultEvery
+	s.faultStatus = faultStatus
+	s.faultRetryAfter = faultRetryAfter
+
+	srv := &http.Server{Handler: s.handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	log.Printf("baton-jamf mock server listening on %s", baseURL)
+	log.Printf("Connect with:")
+	log.Printf("  ./baton-jamf \\")
+	log.Printf("    --username %s \\", username) //nolint:gosec // intentional: test server logs its own config
+	log.Printf("    --password %s \\", password) //nolint:gosec // intentional: test server logs its own config
+	log.Printf("    --instance-url %s", baseURL)
+
+	if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
 
+// handler routes every mocked endpoint to s.
+func (s *server) handler() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/v1/auth/token", s.handleCreateToken)
+	mux.HandleFunc("/api/oauth/token", s.handleCreateOAuthToken)
 	mux.HandleFunc("/api/v1/auth/keep-alive", s.handleKeepAlive)
 	mux.HandleFunc("/api/v1/auth", s.handleTokenDetails)
 	mux.HandleFunc("/api/v1/api-role-privileges", s.handleListPrivileges)
+	mux.HandleFunc("/api/v1/api-roles", s.handleListAPIRoles)
+	mux.HandleFunc("/api/v1/api-integrations", s.handleListAPIIntegrations)
 
 	mux.HandleFunc("/JSSResource/users", s.handleListUsers)
 	mux.HandleFunc("/JSSResource/users/id/", s.handleUserByID)
 	mux.HandleFunc("/JSSResource/users/name/", s.handleUserByName)
+	mux.HandleFunc("/JSSResource/users/email/", s.handleUsersByEmail)
 
 	mux.HandleFunc("/JSSResource/accounts", s.handleListAccounts)
 	mux.HandleFunc("/JSSResource/accounts/userid/", s.handleAccountByID)
@@ -850,17 +2682,37 @@ func run() error {
 
 	mux.HandleFunc("/JSSResource/sites", s.handleListSites)
 
-	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
-
-	log.Printf("baton-jamf mock server listening on %s", baseURL)
-	log.Printf("Connect with:")
-	log.Printf("  ./baton-jamf \\")
-	log.Printf("    --username %s \\", username) //nolint:gosec // intentional: test server logs its own config
-	log.Printf("    --password %s \\", password) //nolint:gosec // intentional: test server logs its own config
-	log.Printf("    --instance-url %s", baseURL)
-
-	if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
-		return err
-	}
-	return nil
+	mux.HandleFunc("/JSSResource/ldapservers", s.handleListLDAPServers)
+	mux.HandleFunc("/JSSResource/ldapservers/id/", s.handleLDAPServerByID)
+
+	mux.HandleFunc("/api/v1/computers-inventory", s.handleComputersInventory)
+	mux.HandleFunc("/api/v2/mobile-devices", s.handleMobileDevices)
+	mux.HandleFunc("/JSSResource/policies", s.handleListPolicies)
+	mux.HandleFunc("/JSSResource/policies/id/", s.handlePolicyByID)
+	mux.HandleFunc("/api/v2/mobile-devices/detail", s.handleMobileDevicesDetail)
+	mux.HandleFunc("/api/v2/mobile-devices/", s.handleMobileDeviceByID)
+	mux.HandleFunc("/api/v1/computers-inventory-detail/", s.handleComputerInventoryDetail)
+	mux.HandleFunc("/api/v2/mdm/commands", s.handleMDMCommands)
+	mux.HandleFunc("/api/v1/computer-inventory/", s.handleRemoveMDMProfile)
+	mux.HandleFunc("/api/v1/mdm/renew-profile", s.handleRenewMDMProfile)
+	mux.HandleFunc("/api/v1/buildings", s.handleBuildings)
+	mux.HandleFunc("/JSSResource/macapplications", s.handleListMacApplications)
+	mux.HandleFunc("/JSSResource/macapplications/id/", s.handleMacApplicationByID)
+	mux.HandleFunc("/JSSResource/mobiledeviceapplications", s.handleListMobileDeviceApplications)
+	mux.HandleFunc("/JSSResource/mobiledeviceapplications/id/", s.handleMobileDeviceApplicationByID)
+	mux.HandleFunc("/JSSResource/vppassignments", s.handleListVPPAssignments)
+	mux.HandleFunc("/JSSResource/vppassignments/id/", s.handleVPPAssignmentByID)
+	mux.HandleFunc("/api/v1/departments", s.handleDepartments)
+	mux.HandleFunc("/JSSResource/osxconfigurationprofiles", s.handleListMacOSConfigurationProfiles)
+	mux.HandleFunc("/JSSResource/osxconfigurationprofiles/id/", s.handleMacOSConfigurationProfileByID)
+	mux.HandleFunc("/JSSResource/mobiledeviceconfigurationprofiles", s.handleListMobileDeviceConfigurationProfiles)
+	mux.HandleFunc("/JSSResource/mobiledeviceconfigurationprofiles/id/", s.handleMobileDeviceConfigurationProfileByID)
+
+	mux.HandleFunc("/JSSResource/computergroups", s.handleListComputerGroups)
+	mux.HandleFunc("/JSSResource/computergroups/id/", s.handleComputerGroupByID)
+
+	mux.HandleFunc("/JSSResource/mobiledevicegroups", s.handleListMobileDeviceGroups)
+	mux.HandleFunc("/JSSResource/mobiledevicegroups/id/", s.handleMobileDeviceGroupByID)
+
+	return s.injectFaults(mux)
 }
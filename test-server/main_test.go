@@ -0,0 +1,613 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	cfg "github.com/conductorone/baton-jamf/pkg/config"
+	"github.com/conductorone/baton-jamf/pkg/connector"
+	"github.com/conductorone/baton-jamf/pkg/jamf"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/cli"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// startServer runs the mock with tokens that expire after ttl.
+func startServer(t *testing.T, ttl time.Duration) (*server, *httptest.Server) {
+	t.Helper()
+	s := newServer(defaultUsername, defaultPassword, defaultClientID, defaultClientSecret, defaultToken)
+	s.tokenTTL = ttl
+	ts := httptest.NewServer(s.handler())
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func newClient(t *testing.T, url string, credentials jamf.Credentials) *jamf.Client {
+	t.Helper()
+	httpClient, err := uhttp.NewClient(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return jamf.NewClient(uhttp.NewBaseHttpClient(httpClient), credentials, url)
+}
+
+func tokenCounts(s *server) (issued int, rejected int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokensIssued, s.tokensRejected
+}
+
+var testCredentials = map[string]jamf.Credentials{
+	"user account": {Username: defaultUsername, Password: defaultPassword},
+	"api client":   {ClientID: defaultClientID, ClientSecret: defaultClientSecret},
+}
+
+// TestShortLivedTokensRefreshAheadOfExpiry makes requests across several
+// token lifetimes and checks none of them is ever sent with an expired token.
+func TestShortLivedTokensRefreshAheadOfExpiry(t *testing.T) {
+	for name, credentials := range testCredentials {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, ts := startServer(t, time.Second)
+			// Reads go around the HTTP cache so each one reaches the server.
+			ctx := jamf.WithoutCache(context.Background())
+			client := newClient(t, ts.URL, credentials)
+
+			for i := 0; i < 3; i++ {
+				if _, err := client.GetSites(ctx); err != nil {
+					t.Fatalf("request %d: unexpected error: %v", i, err)
+				}
+				time.Sleep(850 * time.Millisecond)
+			}
+			// Past expiry, the token has to be replaced outright.
+			time.Sleep(time.Second)
+			if _, err := client.GetSites(ctx); err != nil {
+				t.Fatalf("request after expiry: unexpected error: %v", err)
+			}
+
+			issued, rejected := tokenCounts(s)
+			if rejected != 0 {
+				t.Errorf("expected no request to be rejected, got %d", rejected)
+			}
+			if issued != 4 {
+				t.Errorf("expected 4 tokens to be issued, got %d", issued)
+			}
+		})
+	}
+}
+
+// TestConcurrentRequestsShareOneRefresh checks that requests racing past
+// the refresh point wait for a single refresh instead of each starting one.
+func TestConcurrentRequestsShareOneRefresh(t *testing.T) {
+	for name, credentials := range testCredentials {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, ts := startServer(t, time.Second)
+			ctx := jamf.WithoutCache(context.Background())
+			client := newClient(t, ts.URL, credentials)
+			if err := client.Authenticate(ctx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			time.Sleep(850 * time.Millisecond)
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 16)
+			for i := 0; i < 16; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := client.GetSites(ctx); err != nil {
+						errs <- err
+					}
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			issued, rejected := tokenCounts(s)
+			if rejected != 0 {
+				t.Errorf("expected no request to be rejected, got %d", rejected)
+			}
+			if issued != 2 {
+				t.Errorf("expected the initial token and a single refresh, got %d tokens", issued)
+			}
+		})
+	}
+}
+
+// startFaultyServer runs the mock failing every nth API request with status.
+// Injected failures carry "Retry-After: 0" so retries don't slow the tests.
+func startFaultyServer(t *testing.T, every, status int) (*server, *httptest.Server) {
+	t.Helper()
+	s, ts := startServer(t, time.Hour)
+	s.faultEvery = every
+	s.faultStatus = status
+	s.faultRetryAfter = "0"
+	return s, ts
+}
+
+func faultCounts(s *server) (requests int, injected int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.apiRequests, s.faultsInjected
+}
+
+// TestRateLimitedReadsAreRetried syncs users while every other request is
+// rate limited or shed, and checks every read eventually succeeds.
+func TestRateLimitedReadsAreRetried(t *testing.T) {
+	for _, code := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		t.Run(strconv.Itoa(code), func(t *testing.T) {
+			t.Parallel()
+			s, ts := startFaultyServer(t, 2, code)
+			ctx := jamf.WithoutCache(context.Background())
+			client := newClient(t, ts.URL, testCredentials["user account"])
+
+			users, err := client.GetUsers(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(users) != 5 {
+				t.Errorf("expected 5 users, got %d", len(users))
+			}
+			if _, injected := faultCounts(s); injected == 0 {
+				t.Error("expected faults to be injected")
+			}
+		})
+	}
+}
+
+// TestRetriesStopAtMaxAttempts checks a request that keeps failing is sent
+// MaxAttempts times and then surfaces the failure.
+func TestRetriesStopAtMaxAttempts(t *testing.T) {
+	s, ts := startFaultyServer(t, 1, http.StatusServiceUnavailable)
+	ctx := jamf.WithoutCache(context.Background())
+	client := newClient(t, ts.URL, testCredentials["user account"])
+	client.SetRetryPolicy(jamf.RetryPolicy{MaxAttempts: 3, Budget: time.Minute})
+
+	_, err := client.GetSites(ctx)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	if requests, _ := faultCounts(s); requests != 3 {
+		t.Errorf("expected 3 attempts, got %d", requests)
+	}
+}
+
+// TestNonIdempotentWritesAreNotRetried checks a rate-limited POST isn't
+// resent: Jamf may have created the user before shedding the response.
+func TestNonIdempotentWritesAreNotRetried(t *testing.T) {
+	s, ts := startFaultyServer(t, 1, http.StatusTooManyRequests)
+	client := newClient(t, ts.URL, testCredentials["user account"])
+
+	if err := client.CreateUser(context.Background(), "frank.castle", "Frank Castle", "frank@example.com"); err == nil {
+		t.Fatal("expected the rate-limited create to fail")
+	}
+	if requests, _ := faultCounts(s); requests != 1 {
+		t.Errorf("expected a single attempt, got %d", requests)
+	}
+}
+
+// TestAPIRolesAreReadAcrossPages seeds more API roles than fit in one page
+// and checks the client follows the Jamf Pro API's page / page-size
+// pagination to the end.
+func TestAPIRolesAreReadAcrossPages(t *testing.T) {
+	s, ts := startServer(t, time.Hour)
+	s.mu.Lock()
+	for i := len(s.apiRoles); i < 250; i++ {
+		s.apiRoles = append(s.apiRoles, jamf.APIRole{ID: strconv.Itoa(i + 1), DisplayName: "role-" + strconv.Itoa(i+1)})
+	}
+	s.mu.Unlock()
+
+	client := newClient(t, ts.URL, testCredentials["user account"])
+	roles, err := client.GetAPIRoles(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(roles) != 250 {
+		t.Fatalf("expected 250 API roles, got %d", len(roles))
+	}
+	if roles[0].DisplayName != "Device Auditors" || roles[249].ID != "250" {
+		t.Errorf("expected roles in server order, got first %q and last %q", roles[0].DisplayName, roles[249].ID)
+	}
+
+	integrations, err := client.GetAPIIntegrations(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(integrations) != 2 || integrations[1].Enabled {
+		t.Errorf("expected the 2 seeded integrations with the second disabled, got %+v", integrations)
+	}
+}
+
+// TestComputerGroupMembershipChanges adds a computer to a static computer
+// group and removes it again through the client, and checks a smart group
+// refuses the change.
+func TestComputerGroupMembershipChanges(t *testing.T) {
+	_, ts := startServer(t, time.Hour)
+	client := newClient(t, ts.URL, testCredentials["user account"])
+	ctx := jamf.WithoutCache(context.Background())
+
+	members := func(groupId int) []int {
+		t.Helper()
+		group, err := client.GetComputerGroupDetails(ctx, groupId)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var ids []int
+		for _, c := range group.Computers {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	if err := client.AddComputerGroupMember(ctx, 402, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := members(402); !slices.Equal(got, []int{2, 3}) {
+		t.Fatalf("expected computers [2 3] after the addition, got %v", got)
+	}
+
+	if err := client.RemoveComputerGroupMember(ctx, 402, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := members(402); !slices.Equal(got, []int{3}) {
+		t.Fatalf("expected computers [3] after the deletion, got %v", got)
+	}
+
+	if err := client.AddComputerGroupMember(ctx, 401, 3); err == nil {
+		t.Fatal("expected adding to a smart group to fail")
+	}
+}
+
+// TestDeviceLocationsAreRead checks the client reads the building and
+// department of every computer and mobile device, which policy and
+// configuration profile scopes are resolved against, without pulling the
+// other inventory sections.
+func TestDeviceLocationsAreRead(t *testing.T) {
+	_, ts := startServer(t, time.Hour)
+	client := newClient(t, ts.URL, testCredentials["user account"])
+
+	computers, err := client.GetComputerLocations(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(computers) != 3 {
+		t.Fatalf("expected 3 computers, got %d", len(computers))
+	}
+	if computers[0].General != nil {
+		t.Error("expected only the USER_AND_LOCATION section to be requested")
+	}
+	if ul := computers[1].UserAndLocation; ul == nil || ul.BuildingID != "1" || ul.DepartmentID != "2" {
+		t.Errorf("expected computer 2 in building 1, department 2, got %+v", ul)
+	}
+
+	mobileDevices, err := client.GetMobileDeviceLocations(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mobileDevices) != 2 {
+		t.Fatalf("expected 2 mobile devices, got %d", len(mobileDevices))
+	}
+	if ul := mobileDevices[1].UserAndLocation; mobileDevices[1].MobileDeviceID != "2" || ul == nil || ul.BuildingID != "2" {
+		t.Errorf("expected mobile device 2 in building 2, got %+v", mobileDevices[1])
+	}
+}
+
+// TestDeviceAssignmentChanges checks device assignees are written back with
+// JSON PATCH requests, and that clearing one leaves the rest of the location.
+func TestDeviceAssignmentChanges(t *testing.T) {
+	_, ts := startServer(t, time.Hour)
+	client := newClient(t, ts.URL, testCredentials["user account"])
+	ctx := jamf.WithoutCache(context.Background())
+
+	username, realName, email := "carol.smith", "Carol Smith", "carol.smith@example.com"
+	err := client.UpdateComputerUserAndLocation(ctx, 3, jamf.ComputerUserAndLocationUpdate{Username: &username, Realname: &realName, Email: &email})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	computer, err := client.GetComputerInventoryDetail(ctx, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ul := computer.UserAndLocation; ul == nil || ul.Username != username || ul.EmailAddr() != email || ul.BuildingID != "2" {
+		t.Errorf("expected computer 3 assigned to carol.smith in building 2, got %+v", ul)
+	}
+
+	empty := ""
+	err = client.UpdateMobileDeviceLocation(ctx, 2, jamf.MobileDeviceLocationUpdate{Username: &empty, RealName: &empty, EmailAddress: &empty})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	device, err := client.GetMobileDevice(ctx, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if device.Username != "" {
+		t.Errorf("expected mobile device 2 to be unassigned, got %q", device.Username)
+	}
+	locations, err := client.GetMobileDeviceLocations(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ul := locations[1].UserAndLocation; ul == nil || ul.Username != "" || ul.BuildingID != "2" {
+		t.Errorf("expected mobile device 2 unassigned in building 2, got %+v", ul)
+	}
+
+	if err := client.UpdateComputerUserAndLocation(ctx, 99, jamf.ComputerUserAndLocationUpdate{Username: &username}); !jamf.IsNotFoundError(err) {
+		t.Errorf("expected a not found error for an unknown computer, got %v", err)
+	}
+}
+
+// TestMDMCommandsAreSent checks MDM commands reach the device by its
+// management ID and come back with the UUID Jamf queued them under.
+func TestMDMCommandsAreSent(t *testing.T) {
+	s, ts := startServer(t, time.Hour)
+	client := newClient(t, ts.URL, testCredentials["user account"])
+	ctx := jamf.WithoutCache(context.Background())
+
+	computer, err := client.GetComputerInventoryDetail(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lockUUID, err := client.SendMDMCommand(ctx, computer.General.ManagementID, jamf.MDMCommandData{CommandType: jamf.MDMCommandDeviceLock, Pin: "123456"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	removeUUID, err := client.RemoveComputerMDMProfile(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.RenewMDMProfile(ctx, computer.UDID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s.mu.Lock()
+	commands := slices.Clone(s.mdmCommands)
+	s.mu.Unlock()
+	if len(commands) != 3 {
+		t.Fatalf("expected 3 commands, got %+v", commands)
+	}
+	if lock := commands[0]; lock.uuid != lockUUID || lock.managementID != computer.General.ManagementID || lock.pin != "123456" {
+		t.Errorf("expected the lock command %s for computer 1 with its pin, got %+v", lockUUID, lock)
+	}
+	if commands[1].uuid != removeUUID || commands[1].commandType != "REMOVE_MDM_PROFILE" {
+		t.Errorf("expected the MDM profile removal %s, got %+v", removeUUID, commands[1])
+	}
+
+	if _, err := client.SendMDMCommand(ctx, "unknown", jamf.MDMCommandData{CommandType: jamf.MDMCommandRestartDevice}); err == nil {
+		t.Error("expected a command for an unknown management ID to fail")
+	}
+	if err := client.RenewMDMProfile(ctx, "unknown"); err == nil {
+		t.Error("expected renewing an unknown device's profile to fail")
+	}
+}
+
+func TestUserAccountIsDisabledAndLeavesItsGroups(t *testing.T) {
+	_, ts := startServer(t, time.Hour)
+	client := newClient(t, ts.URL, testCredentials["user account"])
+	ctx := jamf.WithoutCache(context.Background())
+
+	account, err := client.GetUserAccountByName(ctx, "admin3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(account.Groups) != 2 {
+		t.Fatalf("expected admin3 to be in 2 groups, got %+v", account.Groups)
+	}
+
+	if err := client.UpdateUserAccount(ctx, account.ID, jamf.UserAccountUpdateBody{Groups: jamf.NewAccountGroups(nil)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.UpdateUserAccount(ctx, account.ID, jamf.UserAccountUpdateBody{Enabled: "Disabled"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	account, err = client.GetUserAccountByName(ctx, "admin3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.Enabled != "Disabled" || len(account.Groups) != 0 {
+		t.Errorf("expected admin3 to be disabled and in no groups, got %q in %+v", account.Enabled, account.Groups)
+	}
+
+	if err := client.UpdateUserAccount(ctx, account.ID, jamf.UserAccountUpdateBody{Enabled: "Paused"}); err == nil {
+		t.Error("expected an unknown enabled value to be refused")
+	}
+}
+
+func TestUserAccountPasswordIsSet(t *testing.T) {
+	s, ts := startServer(t, time.Hour)
+	client := newClient(t, ts.URL, testCredentials["user account"])
+	ctx := jamf.WithoutCache(context.Background())
+
+	if err := client.UpdateUserAccount(ctx, 101, jamf.UserAccountUpdateBody{Password: "n3w-Passw0rd!"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.mu.Lock()
+	password := s.accountPasswords[101]
+	s.mu.Unlock()
+	if password != "n3w-Passw0rd!" {
+		t.Errorf("expected admin1's password to be set, got %q", password)
+	}
+
+	if err := client.UpdateUserAccount(ctx, 104, jamf.UserAccountUpdateBody{Password: "n3w-Passw0rd!"}); err == nil {
+		t.Error("expected setting a directory account's password to be refused")
+	}
+}
+
+// TestLocalAccountsAreNotLDAP checks that the "None" ldap_server the Classic
+// API reports for local accounts and groups isn't mistaken for a directory.
+func TestLocalAccountsAreNotLDAP(t *testing.T) {
+	_, ts := startServer(t, time.Hour)
+	client := newClient(t, ts.URL, testCredentials["user account"])
+
+	userAccounts, groups, err := client.GetAccounts(jamf.WithoutCache(context.Background()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, account := range userAccounts {
+		if want := account.Name == "admin4"; account.IsLDAP() != want {
+			t.Errorf("expected %s IsLDAP to be %t, ldap_server %+v", account.Name, want, account.LDAPServer)
+		}
+	}
+	for _, group := range groups {
+		if want := group.Name == "group-directory-auditors"; group.IsLDAP() != want {
+			t.Errorf("expected %s IsLDAP to be %t, ldap_server %+v", group.Name, want, group.LDAPServer)
+		}
+	}
+}
+
+// offboard runs offboard_user through a connector configured by cc, pointed at
+// ts, and returns whether it succeeded and its steps.
+func offboard(t *testing.T, ts *httptest.Server, cc *cfg.Jamf, args map[string]any) (bool, []string) {
+	t.Helper()
+	ctx := context.Background()
+	cc.Username, cc.Password, cc.InstanceUrl = defaultUsername, defaultPassword, ts.URL
+	cb, opts, err := connector.New(ctx, cc, &cli.ConnectorOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c, err := connectorbuilder.NewConnector(ctx, cb, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actionArgs, err := structpb.NewStruct(args)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := c.InvokeAction(ctx, v2.InvokeActionRequest_builder{
+		Name:       "offboard_user",
+		Args:       actionArgs,
+		InlineWait: durationpb.New(10 * time.Second),
+	}.Build())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetStatus() != v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE {
+		t.Fatalf("expected offboard_user to complete, got %s", resp.GetStatus())
+	}
+	fields := resp.GetResponse().GetFields()
+	var steps []string
+	for _, step := range fields["steps"].GetListValue().GetValues() {
+		steps = append(steps, step.GetStringValue())
+	}
+	return fields["success"].GetBoolValue(), steps
+}
+
+// TestOffboardUser offboards users and user accounts end to end: finding them
+// by email, taking them out of their groups, unassigning their devices, and
+// disabling or deleting them.
+func TestOffboardUser(t *testing.T) {
+	t.Run("user found by email", func(t *testing.T) {
+		s, ts := startServer(t, time.Hour)
+		ok, steps := offboard(t, ts, &cfg.Jamf{}, map[string]any{
+			"login": "john.appleseed@example.com", "lock_devices": true, "pin": "123456",
+		})
+		if !ok {
+			t.Fatalf("expected the offboarding to succeed, got %q", steps)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if slices.ContainsFunc(s.userGroups[301].Users, func(u jamf.User) bool { return u.ID == 1 }) {
+			t.Error("expected john.appleseed to leave usergroup-eng")
+		}
+		if s.computerInventory[0].UserAndLocation.Username != "" || s.mobileDeviceDetails[0].UserAndLocation.Username != "" {
+			t.Error("expected john.appleseed's computer and iPhone to be unassigned")
+		}
+		if len(s.mdmCommands) != 2 {
+			t.Errorf("expected both devices to be locked, got %+v", s.mdmCommands)
+		}
+		if s.users[1] == nil {
+			t.Error("expected john.appleseed to be kept without delete_user")
+		}
+		if !slices.Contains(steps, "find_user_account john.appleseed@example.com: skipped (no Jamf user account matches)") {
+			t.Errorf("expected the missing user account to be reported, got %q", steps)
+		}
+	})
+
+	t.Run("device that fails to lock stays assigned", func(t *testing.T) {
+		s, ts := startServer(t, time.Hour)
+		ok, steps := offboard(t, ts, &cfg.Jamf{}, map[string]any{
+			"login": "jane.doe", "lock_devices": true, "delete_user": true,
+		})
+		if ok {
+			t.Fatalf("expected the offboarding to fail without a PIN for jane.doe's computer, got %q", steps)
+		}
+		if !slices.Contains(steps, "unassign_device computer 2: skipped (the lock failed)") {
+			t.Errorf("expected the unlocked computer's unassignment to be skipped, got %q", steps)
+		}
+		if !slices.Contains(steps, "remove_from_user_group user group usergroup-sales (302): skipped (smart group; its membership is computed by Jamf)") {
+			t.Errorf("expected the smart group to be left alone, got %q", steps)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.computerInventory[1].UserAndLocation.Username != "jane.doe" {
+			t.Error("expected jane.doe's computer to stay assigned")
+		}
+		if s.users[2] != nil {
+			t.Error("expected jane.doe to be deleted")
+		}
+	})
+
+	t.Run("user account disabled", func(t *testing.T) {
+		s, ts := startServer(t, time.Hour)
+		ok, steps := offboard(t, ts, &cfg.Jamf{}, map[string]any{"login": "admin3"})
+		if !ok {
+			t.Fatalf("expected the offboarding to succeed, got %q", steps)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.accounts[103] == nil || s.accounts[103].Enabled != disabledValue {
+			t.Error("expected admin3 to be disabled")
+		}
+		if groups := s.accountGroupsLocked(103); len(groups) != 0 {
+			t.Errorf("expected admin3 to leave its admin groups, got %+v", groups)
+		}
+	})
+
+	t.Run("user account deleted", func(t *testing.T) {
+		s, ts := startServer(t, time.Hour)
+		ok, steps := offboard(t, ts, &cfg.Jamf{}, map[string]any{"login": "admin1", "delete_account": true})
+		if !ok {
+			t.Fatalf("expected the offboarding to succeed, got %q", steps)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.accounts[101] != nil {
+			t.Error("expected admin1 to be deleted")
+		}
+	})
+
+	t.Run("user account disabled instead of deleted", func(t *testing.T) {
+		s, ts := startServer(t, time.Hour)
+		ok, steps := offboard(t, ts, &cfg.Jamf{DisableUserAccountsOnDelete: true}, map[string]any{"login": "admin1", "delete_account": true})
+		if !ok {
+			t.Fatalf("expected the offboarding to succeed, got %q", steps)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.accounts[101] == nil || s.accounts[101].Enabled != disabledValue {
+			t.Error("expected admin1 to be disabled rather than deleted")
+		}
+	})
+}